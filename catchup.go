@@ -0,0 +1,136 @@
+package tail
+
+import (
+	"cmp"
+	"compress/bzip2"
+	"compress/gzip"
+	"compress/zlib"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// rotatedSuffix matches suffix of rotated file name, e.g. ".1" or
+// "-20261016".
+var rotatedSuffix = regexp.MustCompile(`^[._-][0-9][0-9._T-]*(\.gz|\.bz2|\.zlib)?$`)
+
+// readRotated makes Tail read rotated files before the file opened by
+// Follow: all of them or, with [Resume], starting from the file matching
+// saved position. It returns false and does nothing if no rotated file
+// matches saved position.
+func (t *Tail) readRotated() bool {
+	files := t.openRotated()
+	if t.resume != nil {
+		i := slices.IndexFunc(files, func(f *trackedFile) bool { return f.Match(*t.resume) })
+		if i < 0 {
+			for _, f := range files {
+				f.Close()
+			}
+			return false
+		}
+		if _, err := files[i].Seek(t.resume.Offset, io.SeekStart); err != nil {
+			t.logf(slog.LevelWarn, files[i], err, "cannot seek to saved position",
+				"tail: %q: cannot seek to saved position: %s", files[i].path, err)
+			files[i].Close()
+			if files[i].Open() != nil { // Reopen to read from the beginning.
+				i++
+			}
+		}
+		for _, f := range files[:i] {
+			f.Close()
+		}
+		files = files[i:]
+	}
+	if len(files) > 0 {
+		t.queue = append(files[1:], t.f)
+		t.f = files[0]
+	}
+	return true
+}
+
+// openRotated returns opened rotated files of t.path, oldest first.
+func (t *Tail) openRotated() []*trackedFile {
+	dir, name := filepath.Split(t.path)
+	entries, _ := os.ReadDir(filepath.Clean(dir))
+	names := make(map[string]bool, len(entries))
+	for _, entry := range entries {
+		names[entry.Name()] = true
+	}
+	var files []*trackedFile
+	for _, entry := range entries {
+		suffix, ok := strings.CutPrefix(entry.Name(), name)
+		if !ok || !rotatedSuffix.MatchString(suffix) {
+			continue
+		}
+		ext := filepath.Ext(entry.Name())
+		unzip := unzipFunc(ext)
+		if unzip != nil && names[strings.TrimSuffix(entry.Name(), ext)] {
+			continue // File is being compressed right now.
+		}
+		f := newTrackedFile(t.fctx, dir+entry.Name())
+		f.unzip = unzip
+		if f.Open() != nil {
+			continue
+		}
+		if !f.Usual() || t.f.Opened() && os.SameFile(f.info, t.f.info) {
+			f.Close()
+			continue
+		}
+		files = append(files, f)
+	}
+	slices.SortStableFunc(files, func(a, b *trackedFile) int {
+		if c := a.info.ModTime().Compare(b.info.ModTime()); c != 0 {
+			return c
+		}
+		return compareRotatedSuffix(
+			strings.TrimPrefix(filepath.Base(a.path), name),
+			strings.TrimPrefix(filepath.Base(b.path), name))
+	})
+	return files
+}
+
+// compareRotatedSuffix compares suffixes of rotated files with same
+// modification time: numbered file (".1", ".2.gz") is older if it has
+// larger number, dated file ("-20261016") is older if it has earlier date.
+func compareRotatedSuffix(a, b string) int {
+	numA, okA := rotatedNumber(a)
+	numB, okB := rotatedNumber(b)
+	if okA && okB {
+		return cmp.Compare(numB, numA)
+	}
+	return strings.Compare(a, b)
+}
+
+// rotatedNumber returns number from numbered suffix of rotated file.
+// It returns false for dated suffix.
+func rotatedNumber(suffix string) (int, bool) {
+	if ext := filepath.Ext(suffix); unzipFunc(ext) != nil {
+		suffix = strings.TrimSuffix(suffix, ext)
+	}
+	const minDateLen = len("20261016")
+	if len(suffix) < 2 || len(suffix)-1 >= minDateLen {
+		return 0, false
+	}
+	n, err := strconv.Atoi(suffix[1:])
+	return n, err == nil
+}
+
+// unzipFunc returns decompressor for extension of rotated file or nil if
+// extension is not supported.
+func unzipFunc(ext string) func(io.Reader) (io.Reader, error) {
+	switch ext {
+	case ".gz":
+		return func(r io.Reader) (io.Reader, error) { return gzip.NewReader(r) }
+	case ".bz2":
+		return func(r io.Reader) (io.Reader, error) { return bzip2.NewReader(r), nil }
+	case ".zlib":
+		return func(r io.Reader) (io.Reader, error) { return zlib.NewReader(r) }
+	default:
+		return nil
+	}
+}
@@ -0,0 +1,174 @@
+package tail //nolint:testpackage // TODO
+
+import (
+	"bytes"
+	"compress/gzip"
+	"compress/zlib"
+	"hash/fnv"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/powerman/check"
+)
+
+func TestCatchUp(tt *testing.T) {
+	tt.Parallel()
+	t := check.Must(tt)
+	path := filepath.Join(t.TempDir(), "log")
+	mtime := time.Now().Add(-time.Hour)
+	for _, file := range []struct{ suffix, data string }{
+		{".2", "a\n"},
+		{"-20261016", "b\nb\n"},
+		{".1", "c\n"},
+		{".old", "ignored\n"},
+		{"", "d\n"},
+	} {
+		t.Nil(os.WriteFile(path+file.suffix, []byte(file.data), 0o600))
+		t.Nil(os.Chtimes(path+file.suffix, mtime, mtime))
+		mtime = mtime.Add(time.Minute)
+	}
+
+	tail := startTestTail(t, path, CatchUp())
+	tail.WantChunkPath(path+".2", "a\n")
+	tail.WantChunkPath(path+"-20261016", "b\n")
+	pos, err := tail.Position()
+	t.Nil(err)
+	tail.WantChunkPath(path+"-20261016", "b\n")
+	tail.WantChunkPath(path+".1", "c\n")
+	tail.WantChunkPath(path, "d\n")
+
+	tail = startTestTail(t, path, CatchUp(), Resume(pos, io.SeekEnd))
+	tail.WantChunkPath(path+"-20261016", "b\n")
+	tail.WantChunkPath(path+".1", "c\n")
+	tail.WantChunkPath(path, "d\n")
+
+	pos.Offset = 1 << 20 // Does not match any file.
+	tail = startTestTail(t, path, CatchUp(), Resume(pos, io.SeekEnd))
+	t.Nil(os.WriteFile(path, []byte("d\ne\n"), 0o600))
+	tail.WantChunkPath(path, "e\n")
+}
+
+func TestCatchUpSameModTime(tt *testing.T) {
+	tt.Parallel()
+	t := check.Must(tt)
+	path := filepath.Join(t.TempDir(), "log")
+	mtime := time.Now().Add(-time.Hour)
+	for _, suffix := range []string{".1", ".10", ".2.gz", "-20261016", "-20261015"} {
+		data := []byte(suffix + "\n")
+		if strings.HasSuffix(suffix, ".gz") {
+			var buf bytes.Buffer
+			z := gzip.NewWriter(&buf)
+			_, err := z.Write(data)
+			t.Nil(err)
+			t.Nil(z.Close())
+			data = buf.Bytes()
+		}
+		t.Nil(os.WriteFile(path+suffix, data, 0o600))
+		t.Nil(os.Chtimes(path+suffix, mtime, mtime))
+	}
+	t.Nil(os.WriteFile(path, nil, 0o600))
+
+	tail := startTestTail(t, path, CatchUp())
+	for _, suffix := range []string{"-20261015", "-20261016", ".10", ".2.gz", ".1"} {
+		tail.WantChunkPath(path+suffix, suffix+"\n")
+	}
+}
+
+func TestCatchUpMissing(tt *testing.T) {
+	tt.Parallel()
+	t := check.Must(tt)
+	path := filepath.Join(t.TempDir(), "log")
+	t.Nil(os.WriteFile(path+".1", []byte("old\n"), 0o600))
+
+	tail := startTestTail(t, path, CatchUp())
+	tail.WantChunkPath(path+".1", "old\n")
+
+	go func() {
+		time.Sleep(pollDelay * 2)
+		t.Nil(os.WriteFile(path, []byte("new\n"), 0o600))
+	}()
+	tail.WantChunkPath(path, "new\n")
+}
+
+func TestCatchUpCompressed(tt *testing.T) {
+	tt.Parallel()
+	t := check.Must(tt)
+	path := filepath.Join(t.TempDir(), "log")
+	writeZ := func(name string, w func(io.Writer) io.WriteCloser, data string) {
+		t.Helper()
+		var buf bytes.Buffer
+		z := w(&buf)
+		_, err := z.Write([]byte(data))
+		t.Nil(err)
+		t.Nil(z.Close())
+		t.Nil(os.WriteFile(name, buf.Bytes(), 0o600))
+	}
+	gz := func(w io.Writer) io.WriteCloser { return gzip.NewWriter(w) }
+	zl := func(w io.Writer) io.WriteCloser { return zlib.NewWriter(w) }
+	mtime := time.Now().Add(-time.Hour)
+	chtimes := func(name string) {
+		t.Helper()
+		t.Nil(os.Chtimes(name, mtime, mtime))
+		mtime = mtime.Add(time.Minute)
+	}
+	writeZ(path+".4.gz", gz, "a\n")
+	chtimes(path + ".4.gz")
+	writeZ(path+".3.zlib", zl, "b\nb\n")
+	chtimes(path + ".3.zlib")
+	writeZ(path+".2.gz", gz, "being compressed")
+	t.Nil(os.WriteFile(path+".2", []byte("c\n"), 0o600))
+	chtimes(path + ".2")
+	t.Nil(os.WriteFile(path+".1", []byte("d\n"), 0o600))
+	chtimes(path + ".1")
+	t.Nil(os.WriteFile(path, []byte("e\n"), 0o600))
+
+	tail := startTestTail(t, path, CatchUp())
+	tail.WantChunkPath(path+".4.gz", "a\n")
+	tail.WantChunkPath(path+".3.zlib", "b\n")
+	tail.WantChunkPath(path+".3.zlib", "b\n")
+	tail.WantChunkPath(path+".2", "c\n")
+	pos, err := tail.Position()
+	t.Nil(err)
+	tail.WantChunkPath(path+".1", "d\n")
+	tail.WantChunkPath(path, "e\n")
+
+	// Position saved before rotated file was compressed.
+	fi, err := os.Stat(path + ".2")
+	t.Nil(err)
+	t.Nil(os.Remove(path + ".2"))
+	writeZ(path+".2.gz", gz, "c\n")
+	t.Nil(os.Chtimes(path+".2.gz", fi.ModTime(), fi.ModTime()))
+	tail = startTestTail(t, path, CatchUp(), Resume(pos, io.SeekEnd))
+	tail.WantChunkPath(path+".1", "d\n")
+	tail.WantChunkPath(path, "e\n")
+}
+
+func TestMatchCompressed(tt *testing.T) {
+	tt.Parallel()
+	t := check.Must(tt)
+	path := filepath.Join(t.TempDir(), "log.1.gz")
+	data := strings.Repeat("x", FingerprintSize*2)
+	h := fnv.New64a()
+	_, err := h.Write([]byte(data[:FingerprintSize]))
+	t.Nil(err)
+	pos := Position{FileID: FileID{}, Offset: int64(len(data)), Fingerprint: h.Sum64()}
+
+	for _, size := range []int{len(data) - 1, len(data), len(data) + 1} {
+		var buf bytes.Buffer
+		z := gzip.NewWriter(&buf)
+		_, err := z.Write([]byte(strings.Repeat("x", size)))
+		t.Nil(err)
+		t.Nil(z.Close())
+		t.Nil(os.WriteFile(path, buf.Bytes(), 0o600))
+
+		f := newTrackedFile(t.Context(), path)
+		f.unzip = unzipFunc(".gz")
+		t.Nil(f.Open())
+		t.Equal(f.Match(pos), size >= len(data), size)
+		f.Close()
+	}
+}
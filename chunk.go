@@ -0,0 +1,37 @@
+package tail
+
+// Chunk is a data read from the file together with information about the
+// file.
+type Chunk struct {
+	FileID
+
+	Data []byte
+	// Path used to open the file.
+	Path string
+	// Offset of Data in the file (for FIFO it's amount of bytes read
+	// from FIFO before Data).
+	Offset int64
+	// Detached is true if Path was pointing to another file or was
+	// inaccessible when ReadChunk returned (e.g. file was renamed or
+	// removed after it was opened). It's always false for rotated files
+	// read by [CatchUp] which are still available by their own Path.
+	Detached bool
+}
+
+// ReadChunk works like Read but also returns information about the file
+// data was read from. Returned Chunk.Data is a slice of p with read data.
+//
+// ReadChunk must not be called simultaneously with Read.
+func (t *Tail) ReadChunk(p []byte) (Chunk, error) {
+	n, err := t.Read(p)
+	if n == 0 {
+		return Chunk{Data: p[:0]}, err
+	}
+	return Chunk{
+		FileID:   t.f.id,
+		Data:     p[:n],
+		Path:     t.f.path,
+		Offset:   t.f.offset - int64(n),
+		Detached: t.f.Detached(),
+	}, err
+}
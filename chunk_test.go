@@ -0,0 +1,52 @@
+package tail //nolint:testpackage // TODO
+
+import (
+	"io"
+	"testing"
+
+	"github.com/powerman/check"
+)
+
+func TestReadChunk(tt *testing.T) {
+	tt.Parallel()
+	t := check.Must(tt)
+	tail := newTestTail(t)
+	tail.Start()
+	want := func(data string, offset int64, detached bool) FileID {
+		t.Helper()
+		c := tail.WantChunk(data, nil)
+		t.Equal(c.Path, tail.path)
+		t.Equal(c.Offset, offset)
+		t.Equal(c.Detached, detached)
+		return c.FileID
+	}
+
+	tail.Write("old1\n")
+	id := want("old1\n", 0, false)
+	t.NotZero(id)
+	tail.Write("old2\n")
+	t.Equal(want("old2\n", 5, false), id)
+
+	tail.Rename()
+	tail.Create()
+	tail.Write("new1\n")
+	tail.WriteOld("old3\n")
+	t.Equal(want("old3\n", 10, true), id)
+	t.NotEqual(want("new1\n", 0, false), id)
+}
+
+func TestReadChunkFollowDescriptor(tt *testing.T) {
+	tt.Parallel()
+	t := check.Must(tt)
+	tail := newTestTail(t)
+	tail.Write("old1\n")
+	tail.Start(Whence(io.SeekStart), FollowDescriptor())
+
+	c := tail.WantChunk("old1\n", nil)
+	t.False(c.Detached)
+	tail.Remove()
+	tail.Write("new1\n")
+	c = tail.WantChunk("new1\n", nil)
+	t.Equal(c.Offset, int64(5))
+	t.True(c.Detached)
+}
@@ -0,0 +1,78 @@
+package tail
+
+// EventType is a type of Tail state change.
+type EventType int
+
+// Event types.
+const (
+	// EventOpened means path was opened (by Follow or after it has
+	// appeared).
+	EventOpened EventType = iota + 1
+	// EventDetached means path no longer points to the file being read
+	// (it was renamed or removed).
+	EventDetached
+	// EventReplaced means path points to a new file, which was opened.
+	// Tail will continue reading the new file after reading rest of the
+	// file being read.
+	EventReplaced
+	// EventDisappeared means path has become inaccessible.
+	EventDisappeared
+	// EventTruncated means file being read was truncated.
+	EventTruncated
+	// EventReadError means reading of the file has failed.
+	EventReadError
+	// EventSwitched means Tail has read the whole detached file and
+	// continues reading the new file.
+	EventSwitched
+)
+
+func (e EventType) String() string {
+	switch e {
+	case EventOpened:
+		return "Opened"
+	case EventDetached:
+		return "Detached"
+	case EventReplaced:
+		return "Replaced"
+	case EventDisappeared:
+		return "Disappeared"
+	case EventTruncated:
+		return "Truncated"
+	case EventReadError:
+		return "ReadError"
+	case EventSwitched:
+		return "Switched"
+	default:
+		return "Unknown"
+	}
+}
+
+// Event describes Tail state change.
+type Event struct {
+	Type EventType
+	// Path of the file event is about: of the new file for EventReplaced,
+	// EventSwitched and EventOpened, of the followed file otherwise
+	// (it differs from path given to Follow only in [Latest] mode and for
+	// rotated files read in [CatchUp] mode).
+	// For EventDisappeared it's the path which has become inaccessible.
+	Path string
+	// Old is the file being read before event (zero if there is no such
+	// file) and OldOffset is the offset in this file.
+	Old       FileID
+	OldOffset int64
+	// New is the file opened or continued with (zero if there is no such
+	// file) and NewOffset is the offset in this file.
+	New       FileID
+	NewOffset int64
+	// Err is set for EventDisappeared and EventReadError.
+	Err error
+}
+
+// emit updates metrics and reports event to a handler set by [Events]
+// option.
+func (t *Tail) emit(ev Event) {
+	t.countEvent(ev)
+	if t.events != nil {
+		t.events(ev)
+	}
+}
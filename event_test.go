@@ -0,0 +1,58 @@
+package tail //nolint:testpackage // TODO
+
+import (
+	"testing"
+
+	"github.com/powerman/check"
+)
+
+func TestEvents(tt *testing.T) {
+	tt.Parallel()
+	t := check.Must(tt)
+	tail := newTestTail(t)
+	stat := func() FileID {
+		t.Helper()
+		fi, err := tail.f.Stat()
+		t.Nil(err)
+		return getFileID(tail.f, fi)
+	}
+
+	tail.Write("old")
+	var events []Event
+	tail.Start(Events(func(ev Event) { events = append(events, ev) }))
+	want := func(wantData string, wantEvents ...Event) {
+		t.Helper()
+		events = nil
+		tail.WantChunk(wantData, nil)
+		t.DeepEqual(events, wantEvents)
+	}
+
+	id1 := stat()
+	t.DeepEqual(events, []Event{{Type: EventOpened, Path: tail.path, New: id1, NewOffset: 3}})
+
+	tail.Write("1")
+	want("1")
+
+	tail.Rename()
+	tail.Create()
+	tail.Write("22")
+	id2 := stat()
+	want("22",
+		Event{Type: EventDetached, Path: tail.path, Old: id1, OldOffset: 4},
+		Event{Type: EventReplaced, Path: tail.path, Old: id1, OldOffset: 4, New: id2},
+		Event{Type: EventSwitched, Path: tail.path, Old: id1, OldOffset: 4, New: id2},
+	)
+
+	tail.Truncate()
+	tail.Write("3")
+	want("3",
+		Event{Type: EventTruncated, Path: tail.path, Old: id2, OldOffset: 2, New: id2},
+	)
+}
+
+func TestEventType(tt *testing.T) {
+	tt.Parallel()
+	t := check.Must(tt)
+	t.Equal(EventSwitched.String(), "Switched")
+	t.Equal(EventType(0).String(), "Unknown")
+}
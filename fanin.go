@@ -0,0 +1,116 @@
+package tail
+
+import (
+	"context"
+	"errors"
+	"io"
+	"sync"
+	"time"
+)
+
+var errFanInTimeout = errors.New("fan-in timeout")
+
+// fanIn passes values from several producer goroutines to a single
+// consumer. Producer waits until consumer is done with the value (i.e.
+// calls recv again), which makes it safe to pass buffers without copying.
+//
+// After ctx is done producers are stopped and consumer gets [io.EOF].
+type fanIn[T any] struct {
+	ctx  context.Context //nolint:containedctx // By design.
+	c    chan fanInItem[T]
+	wg   sync.WaitGroup
+	done chan<- struct{} // Used to return last received value to producer.
+}
+
+type fanInItem[T any] struct {
+	val  T
+	err  error
+	done chan<- struct{}
+}
+
+func newFanIn[T any](ctx context.Context) *fanIn[T] {
+	return &fanIn[T]{
+		ctx:  ctx,
+		c:    make(chan fanInItem[T]),
+		wg:   sync.WaitGroup{},
+		done: nil,
+	}
+}
+
+// spawn runs produce in a new goroutine. Produce must return when send
+// returns false.
+//
+// It must not be called after close.
+func (f *fanIn[T]) spawn(produce func(send func(T, error) bool)) {
+	f.wg.Add(1)
+	go func() {
+		defer f.wg.Done()
+		done := make(chan struct{}, 1)
+		produce(func(val T, err error) bool {
+			select {
+			case f.c <- fanInItem[T]{val: val, err: err, done: done}:
+			case <-f.ctx.Done():
+				return false
+			}
+			select {
+			case <-done:
+				return true
+			case <-f.ctx.Done():
+				return false
+			}
+		})
+	}()
+}
+
+// close makes recv return [io.EOF] after all producers have returned.
+func (f *fanIn[T]) close() {
+	go func() {
+		f.wg.Wait()
+		close(f.c)
+	}()
+}
+
+// recv returns next value and error sent by any producer.
+// It returns errFanInTimeout if timeoutc fires first and [io.EOF] after
+// close was called and all producers have returned or after ctx is done.
+//
+// Value returned by previous recv is returned to its producer.
+func (f *fanIn[T]) recv(timeoutc <-chan time.Time) (val T, err error) {
+	f.release()
+	if f.ctx.Err() != nil {
+		return val, io.EOF
+	}
+	select {
+	case item, ok := <-f.c:
+		if !ok {
+			return val, io.EOF
+		}
+		f.done = item.done
+		return item.val, item.err
+	case <-timeoutc:
+		return val, errFanInTimeout
+	case <-f.ctx.Done():
+		return val, io.EOF
+	}
+}
+
+// release returns value returned by last recv to its producer.
+func (f *fanIn[T]) release() {
+	if f.done != nil {
+		f.done <- struct{}{}
+		f.done = nil
+	}
+}
+
+// followLines returns producer for fanIn which sends lines read from t.
+func followLines(t *Tail) func(send func(Line, error) bool) {
+	return func(send func(Line, error) bool) {
+		r := NewLineReader(t)
+		for {
+			line, err := r.ReadLine()
+			if errors.Is(err, io.EOF) || !send(Line{Path: t.path, Data: line}, err) {
+				return
+			}
+		}
+	}
+}
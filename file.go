@@ -2,9 +2,13 @@ package tail
 
 import (
 	"context"
+	"errors"
+	"io"
 	"os"
 )
 
+var errSeekCompressed = errors.New("seek backward in compressed file is not supported")
+
 type trackedFile struct {
 	*os.File
 
@@ -12,6 +16,12 @@ type trackedFile struct {
 	path   string
 	cancel context.CancelFunc
 	info   os.FileInfo
+	id     FileID
+	offset int64
+	unzip  func(io.Reader) (io.Reader, error) // Set for compressed file.
+	z      io.Reader                          // Decompressed data.
+	fp     uint64                             // Cached fingerprint of FingerprintSize bytes.
+	fpDone bool                               // Set when fp is cached.
 }
 
 func newTrackedFile(ctx context.Context, path string) *trackedFile {
@@ -20,6 +30,12 @@ func newTrackedFile(ctx context.Context, path string) *trackedFile {
 		path:   path,
 		cancel: nil,
 		info:   nil,
+		id:     FileID{},
+		offset: 0,
+		unzip:  nil,
+		z:      nil,
+		fp:     0,
+		fpDone: false,
 		File:   nil,
 	}
 }
@@ -34,6 +50,14 @@ func (f *trackedFile) Open() error {
 		_ = file.Close()
 		return err
 	}
+	var z io.Reader
+	if f.unzip != nil {
+		z, err = f.unzip(file)
+		if err != nil {
+			_ = file.Close()
+			return err
+		}
+	}
 
 	// Make it possible to interrupt f.Read(), which may
 	// block when reading from FIFO or file mounted by network.
@@ -46,7 +70,11 @@ func (f *trackedFile) Open() error {
 
 	f.File = file
 	f.info = fi
+	f.id = getFileID(file, fi)
 	f.cancel = cancel
+	f.offset = 0
+	f.z = z
+	f.fp, f.fpDone = 0, false
 	return nil
 }
 
@@ -68,3 +96,48 @@ func (f *trackedFile) Detached() bool {
 	fi, err := os.Stat(f.path)
 	return err != nil || !os.SameFile(f.info, fi)
 }
+
+func (f *trackedFile) Read(p []byte) (int, error) {
+	var r io.Reader = f.File
+	if f.z != nil {
+		r = f.z
+	}
+	n, err := r.Read(p)
+	f.offset += int64(n)
+	switch {
+	case f.z == nil || err == nil:
+	case errors.Is(err, io.EOF) && n > 0:
+		err = nil // Decompressor may return data with EOF, but Tail expects only data.
+	case !errors.Is(err, io.EOF):
+		f.z = io.MultiReader() // Decompression errors are not recoverable, so report EOF next time.
+	}
+	return n, err
+}
+
+// Seek works as usual for uncompressed file, but for compressed file it
+// supports only seeking forward relative to the beginning of the file.
+func (f *trackedFile) Seek(offset int64, whence int) (int64, error) {
+	if f.z != nil {
+		if whence != io.SeekStart || offset < f.offset {
+			return f.offset, errSeekCompressed
+		}
+		n, err := io.CopyN(io.Discard, f.z, offset-f.offset)
+		f.offset += n
+		return f.offset, err
+	}
+	ret, err := f.File.Seek(offset, whence)
+	if err == nil {
+		f.offset = ret
+	}
+	return ret, err
+}
+
+// Truncated reports whether usual uncompressed file became smaller than
+// current offset.
+func (f *trackedFile) Truncated() bool {
+	if !f.Usual() || f.z != nil {
+		return false
+	}
+	fi, err := f.Stat()
+	return err == nil && fi.Size() < f.offset
+}
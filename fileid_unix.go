@@ -0,0 +1,20 @@
+//go:build !windows
+
+package tail
+
+import (
+	"os"
+	"syscall"
+)
+
+// getFileID returns identity of opened file in a platform-specific way.
+func getFileID(_ *os.File, fi os.FileInfo) FileID {
+	st, ok := fi.Sys().(*syscall.Stat_t)
+	if !ok {
+		return FileID{}
+	}
+	return FileID{
+		Dev: uint64(st.Dev), //nolint:gosec,unconvert // Type differs between platforms.
+		Ino: uint64(st.Ino), //nolint:unconvert // Type differs between platforms.
+	}
+}
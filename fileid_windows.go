@@ -0,0 +1,21 @@
+//go:build windows
+
+package tail
+
+import (
+	"os"
+	"syscall"
+)
+
+// getFileID returns identity of opened file in a platform-specific way.
+func getFileID(f *os.File, _ os.FileInfo) FileID {
+	var d syscall.ByHandleFileInformation
+	err := syscall.GetFileInformationByHandle(syscall.Handle(f.Fd()), &d)
+	if err != nil {
+		return FileID{}
+	}
+	return FileID{
+		Dev: uint64(d.VolumeSerialNumber),
+		Ino: uint64(d.FileIndexHigh)<<32 | uint64(d.FileIndexLow),
+	}
+}
@@ -0,0 +1,161 @@
+package tail
+
+import (
+	"context"
+	"errors"
+	"io"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// globBufSize is the size of buffer used to read each file matching Glob
+// pattern.
+const globBufSize = 32 * 1024
+
+// Glob follows all files matching a pattern.
+type Glob struct {
+	ctx       context.Context //nolint:containedctx // By design.
+	log       Logger
+	pattern   string
+	options   []Option
+	pollDelay time.Duration
+	in        *fanIn[Chunk]
+	mu        sync.Mutex
+	tails     map[string]*Tail
+	pending   bool  // Chunk was not returned by ReadChunk completely.
+	chunk     Chunk // Part of pending chunk not returned by ReadChunk.
+	err       error // Error to return together with the end of chunk.
+}
+
+// FollowGlob starts tracking all files matching pattern (see
+// [filepath.Match] for pattern syntax) using Follow with given options.
+//
+// Pattern is checked for new matching files every [PollDelay]. Files
+// which appear after FollowGlob has returned are followed from the
+// beginning. When file no longer matches pattern (e.g. was removed) it
+// is tracked until all its data is read.
+//
+// The only possible returned error is [filepath.ErrBadPattern].
+func FollowGlob(ctx context.Context, log Logger, pattern string, options ...Option) (*Glob, error) {
+	if _, err := filepath.Match(pattern, ""); err != nil {
+		return nil, err
+	}
+
+	cfg := newTail(ctx, log, pattern, options...) // Used only to get pollDelay and drain.
+
+	g := &Glob{
+		ctx:       ctx,
+		log:       log,
+		pattern:   pattern,
+		options:   options,
+		pollDelay: cfg.pollDelay,
+		in:        newFanIn[Chunk](drainContext(ctx, cfg.drain)),
+		mu:        sync.Mutex{},
+		tails:     make(map[string]*Tail),
+		pending:   false,
+		chunk:     Chunk{},
+		err:       nil,
+	}
+	g.scan(options)
+	go g.run()
+	return g, nil
+}
+
+func (g *Glob) run() {
+	defer g.in.close()
+	delay := time.NewTicker(g.pollDelay)
+	defer delay.Stop()
+	options := append(g.options[:len(g.options):len(g.options)], Whence(io.SeekStart))
+	for {
+		select {
+		case <-delay.C:
+			g.scan(options)
+		case <-g.ctx.Done():
+			return
+		}
+	}
+}
+
+// scan follows new files matching pattern and retires Tail for files
+// which no longer match pattern.
+func (g *Glob) scan(options []Option) {
+	paths, _ := filepath.Glob(g.pattern) // Pattern was checked by FollowGlob.
+	matched := make(map[string]bool, len(paths))
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	for _, path := range paths {
+		matched[path] = true
+		if _, ok := g.tails[path]; !ok {
+			t := Follow(g.ctx, g.log, path, options...)
+			g.tails[path] = t
+			g.in.spawn(g.follow(t))
+		}
+	}
+	for path, t := range g.tails {
+		t.stopAtEOF.Store(!matched[path])
+	}
+}
+
+// follow returns producer for fanIn which sends chunks read from t.
+func (g *Glob) follow(t *Tail) func(send func(Chunk, error) bool) {
+	return func(send func(Chunk, error) bool) {
+		defer func() {
+			t.close()
+			g.mu.Lock()
+			defer g.mu.Unlock()
+			if g.tails[t.path] == t {
+				delete(g.tails, t.path)
+			}
+		}()
+
+		buf := make([]byte, globBufSize)
+		for {
+			chunk, err := t.ReadChunk(buf)
+			if errors.Is(err, io.EOF) {
+				return
+			}
+			if chunk.Path == "" {
+				chunk.Path = t.path
+			}
+			if !send(chunk, err) {
+				return
+			}
+		}
+	}
+}
+
+// ReadChunk works like [Tail.ReadChunk] but returns data from any file
+// matching pattern. If it returns an error except [io.EOF] then
+// Chunk.Path is set to the path of the file related to the error.
+//
+// ReadChunk will return [io.EOF] only after cancelling ctx (and, with
+// [Drain], reading remaining data or drain timeout).
+//
+// ReadChunk must be called until it returns [io.EOF], otherwise
+// background goroutines reading files may not exit until ctx is done
+// (or until drain timeout).
+//
+// ReadChunk must not be called from simultaneous goroutines.
+func (g *Glob) ReadChunk(p []byte) (Chunk, error) {
+	if !g.pending {
+		var err error
+		g.chunk, err = g.in.recv(nil)
+		if errors.Is(err, io.EOF) {
+			return Chunk{Data: p[:0]}, io.EOF
+		}
+		g.pending, g.err = true, err
+	}
+
+	chunk := g.chunk
+	chunk.Data = p[:copy(p, g.chunk.Data)]
+	g.chunk.Data = g.chunk.Data[len(chunk.Data):]
+	g.chunk.Offset += int64(len(chunk.Data))
+	if len(g.chunk.Data) > 0 {
+		return chunk, nil
+	}
+	g.pending = false
+	g.in.release()
+	return chunk, g.err
+}
@@ -0,0 +1,107 @@
+package tail //nolint:testpackage // TODO
+
+import (
+	"context"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/powerman/check"
+)
+
+func TestFollowGlob(tt *testing.T) {
+	tt.Parallel()
+	t := check.Must(tt)
+	dir := t.TempDir()
+	pathA := filepath.Join(dir, "a.log")
+	pathB := filepath.Join(dir, "b.log")
+	fA, err := createFile(pathA)
+	t.Nil(err)
+	defer func() { t.Nil(fA.Close()) }()
+	_, err = fA.WriteString("old\n")
+	t.Nil(err)
+
+	_, err = FollowGlob(t.Context(), LoggerFunc(t.Logf), "[", PollDelay(pollDelay))
+	t.Err(err, filepath.ErrBadPattern)
+
+	ctx, cancel := context.WithCancel(t.Context())
+	defer cancel()
+	g, err := FollowGlob(ctx, LoggerFunc(t.Logf), filepath.Join(dir, "*.log"),
+		PollDelay(pollDelay), PollTimeout(pollTimeout))
+	t.Nil(err)
+	p := make([]byte, 3)
+	want := func(wantPath, wantData string, wantOffset int64) {
+		t.Helper()
+		chunk, err := g.ReadChunk(p)
+		t.Nil(err)
+		t.Equal(chunk.Path, wantPath)
+		t.Equal(string(chunk.Data), wantData)
+		t.Equal(chunk.Offset, wantOffset)
+	}
+
+	_, err = fA.WriteString("a1\n")
+	t.Nil(err)
+	want(pathA, "a1\n", 4)
+
+	fB, err := createFile(pathB)
+	t.Nil(err)
+	defer func() { t.Nil(fB.Close()) }()
+	_, err = fB.WriteString("b1\nb2")
+	t.Nil(err)
+	want(pathB, "b1\n", 0)
+	want(pathB, "b2", 3)
+
+	g.mu.Lock()
+	tailA := g.tails[pathA]
+	g.mu.Unlock()
+	_, err = fA.WriteString("a2\n")
+	t.Nil(err)
+	t.Nil(os.Remove(pathA))
+	time.Sleep(pollDelay * 2)
+	want(pathA, "a2\n", 7)
+	time.Sleep(pollDelay * 3)
+	g.mu.Lock()
+	t.Len(g.tails, 1)
+	g.mu.Unlock()
+	t.NotNil(tailA.fctx.Err()) // Retired Tail has released its files and watcher.
+	_, err = tailA.f.File.Read(p)
+	t.True(errors.Is(err, os.ErrClosed))
+
+	cancel()
+	chunk, err := g.ReadChunk(p)
+	t.Err(err, io.EOF)
+	t.Len(chunk.Data, 0)
+}
+
+func TestFollowGlobDrain(tt *testing.T) {
+	tt.Parallel()
+	t := check.Must(tt)
+	path := filepath.Join(t.TempDir(), "a.log")
+	f, err := createFile(path)
+	t.Nil(err)
+	defer func() { t.Nil(f.Close()) }()
+
+	ctx, cancel := context.WithCancel(t.Context())
+	g, err := FollowGlob(ctx, LoggerFunc(t.Logf), filepath.Join(filepath.Dir(path), "*.log"),
+		PollDelay(pollDelay), PollTimeout(pollTimeout), Drain(pollTimeout))
+	t.Nil(err)
+	p := make([]byte, 64)
+
+	_, err = f.WriteString("a1\n")
+	t.Nil(err)
+	chunk, err := g.ReadChunk(p)
+	t.Nil(err)
+	t.Equal(string(chunk.Data), "a1\n")
+
+	_, err = f.WriteString("a2\n")
+	t.Nil(err)
+	cancel()
+	chunk, err = g.ReadChunk(p)
+	t.Nil(err)
+	t.Equal(string(chunk.Data), "a2\n")
+	_, err = g.ReadChunk(p)
+	t.Err(err, io.EOF)
+}
@@ -33,6 +33,8 @@ type testTail struct {
 
 	t       *check.TB
 	f       *os.File
+	old     *os.File // Previous f, replaced by Create.
+	lines   *LineReader
 	path    string
 	symlink string
 	bufc    chan []byte
@@ -49,6 +51,8 @@ func newTestTail(t *check.TB) *testTail {
 	tail := &testTail{
 		t:       t,
 		f:       f,
+		old:     nil,
+		lines:   nil,
 		path:    f.Name(),
 		symlink: "",
 		bufc:    make(chan []byte),
@@ -61,9 +65,32 @@ func newTestTail(t *check.TB) *testTail {
 	return tail
 }
 
+// startTestTail calls Start for testTail following given path (e.g.
+// pattern or file in t.TempDir()) instead of temporary file.
+func startTestTail(t *check.TB, path string, options ...Option) *testTail {
+	t.Helper()
+	tail := newTestTail(t)
+	tail.path = path
+	tail.Start(options...)
+	return tail
+}
+
 func (tail *testTail) Run(options ...Option) {
+	tail.follow(options)
+	go tail.reader()
+}
+
+// Start works like Run but without background reader, so test should
+// read using ReadChunk, WantChunk, WantLine or WantRecord instead of Want.
+func (tail *testTail) Start(options ...Option) {
+	tail.follow(options)
+	tail.lines = NewLineReader(tail.Tail)
+	close(tail.errc) // There is no background reader to wait for.
+}
+
+func (tail *testTail) follow(options []Option) {
 	if tail.Tail != nil {
-		panic("tail.Run() must be called only once")
+		panic("tail.Run() or tail.Start() must be called only once")
 	}
 	tail.t.Cleanup(tail.Close)
 	ctx, cancel := context.WithCancel(tail.t.Context())
@@ -74,12 +101,11 @@ func (tail *testTail) Run(options ...Option) {
 	} else {
 		tail.Tail = Follow(ctx, LoggerFunc(tail.t.Logf), tail.symlink, options...)
 	}
-	go tail.reader()
 }
 
 func (tail *testTail) Close() {
 	if tail.Tail == nil {
-		panic("tail.Run() must be called before tail.Close()")
+		panic("tail.Run() or tail.Start() must be called before tail.Close()")
 	}
 	t := tail.t
 	t.Helper()
@@ -151,6 +177,47 @@ func (tail *testTail) Want(timeout time.Duration, want string, wanterr error) {
 	}
 }
 
+// WantChunk reads next chunk using buffer of len(want) bytes.
+func (tail *testTail) WantChunk(want string, wanterr error) Chunk {
+	if tail.lines == nil {
+		panic("tail.Start() must be called before tail.WantChunk()")
+	}
+	t := tail.t
+	t.Helper()
+	chunk, err := tail.ReadChunk(make([]byte, max(len(want), 1)))
+	t.Equal(string(chunk.Data), want)
+	t.Err(unwrap(err), wanterr)
+	return chunk
+}
+
+// WantChunkPath works like WantChunk but also checks Chunk.Path.
+func (tail *testTail) WantChunkPath(wantPath, want string) {
+	t := tail.t
+	t.Helper()
+	t.Equal(tail.WantChunk(want, nil).Path, wantPath)
+}
+
+// WantLine reads next line using LineReader.
+func (tail *testTail) WantLine(want string, wanterr error) {
+	if tail.lines == nil {
+		panic("tail.Start() must be called before tail.WantLine()")
+	}
+	t := tail.t
+	t.Helper()
+	line, err := tail.lines.ReadLine()
+	t.Equal(string(line), want)
+	t.Err(unwrap(err), wanterr)
+}
+
+// WantRecord reads next record from r, which should read from tail.
+func (tail *testTail) WantRecord(r interface{ ReadRecord() ([]byte, error) }, want string, wanterr error) {
+	t := tail.t
+	t.Helper()
+	rec, err := r.ReadRecord()
+	t.Equal(string(rec), want)
+	t.Err(unwrap(err), wanterr)
+}
+
 func (tail *testTail) Remove() {
 	_, err := os.Stat(tail.path)
 	if os.IsNotExist(err) {
@@ -194,7 +261,7 @@ func (tail *testTail) Create() {
 	t.Helper()
 	f, err := createFile(tail.path)
 	t.Nil(err)
-	tail.f = f
+	tail.f, tail.old = f, tail.f
 	tail.created = append(tail.created, tail.path)
 	tail.opened = append(tail.opened, f)
 }
@@ -245,6 +312,14 @@ func (tail *testTail) RemoveSymlink() {
 	}
 }
 
+func (tail *testTail) Truncate() {
+	t := tail.t
+	t.Helper()
+	t.Nil(tail.f.Truncate(0))
+	_, err := tail.f.Seek(0, io.SeekStart)
+	t.Nil(err)
+}
+
 func (tail *testTail) Write(s string) {
 	t := tail.t
 	t.Helper()
@@ -252,6 +327,17 @@ func (tail *testTail) Write(s string) {
 	t.Nil(err)
 }
 
+// WriteOld writes to the file which was replaced by last Create.
+func (tail *testTail) WriteOld(s string) {
+	if tail.old == nil {
+		panic("tail.Create() must be called before tail.WriteOld()")
+	}
+	t := tail.t
+	t.Helper()
+	_, err := tail.old.WriteString(s)
+	t.Nil(err)
+}
+
 func (tail *testTail) tempPath() string {
 	t := tail.t
 	t.Helper()
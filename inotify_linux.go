@@ -0,0 +1,290 @@
+//go:build linux
+
+package tail
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"slices"
+	"sync"
+	"time"
+	"unsafe"
+
+	"golang.org/x/sys/unix"
+)
+
+const (
+	inotifyDirMask  = unix.IN_CREATE | unix.IN_MOVED_TO | unix.IN_MOVED_FROM | unix.IN_DELETE | unix.IN_ATTRIB
+	inotifyFileMask = unix.IN_MODIFY | unix.IN_ATTRIB | unix.IN_MOVE_SELF | unix.IN_DELETE_SELF
+	inotifyMaxFiles = 2 // Detached file and the new one.
+)
+
+// sharedInotify is an inotify instance shared by all inotify Watchers
+// because amount of inotify instances per user is limited (by default to
+// 128 in fs.inotify.max_user_instances).
+var sharedInotify struct { //nolint:gochecknoglobals // Shared by all Tails.
+	sync.Mutex
+	in *inotifyInstance
+}
+
+// inotifyInstance routes events of inotify instance to watchers by watch
+// descriptor.
+type inotifyInstance struct {
+	f    *os.File
+	refs int // Protected by sharedInotify.
+	mu   sync.Mutex
+	subs map[int32][]*inotify // Watchers by watch descriptor.
+	err  error                // Protected by mu.
+}
+
+// inotify detects changes of path and files opened from path.
+type inotify struct {
+	in       *inotifyInstance
+	path     string
+	dir      string
+	name     string
+	dirWD    int32   // Protected by in.mu.
+	fileWD   []int32 // Protected by in.mu.
+	released bool    // Protected by in.mu.
+	c        chan struct{}
+	rescan   time.Duration
+	timer    *time.Timer
+}
+
+func newInotify(ctx context.Context, path string, rescan time.Duration) (Watcher, error) {
+	in, err := acquireInotify()
+	if err != nil {
+		return nil, err
+	}
+	w := &inotify{
+		in:       in,
+		path:     path,
+		dir:      filepath.Dir(path),
+		name:     filepath.Base(path),
+		dirWD:    -1,
+		fileWD:   nil,
+		released: false,
+		c:        make(chan struct{}, 1),
+		rescan:   rescan,
+		timer:    nil,
+	}
+	w.timer = time.AfterFunc(rescan, w.notify)
+	go func() {
+		<-ctx.Done()
+		w.timer.Stop()
+		in.release(w)
+	}()
+	return w, nil
+}
+
+// acquireInotify returns shared inotify instance, creating it if needed.
+// Returned instance must be released after use.
+func acquireInotify() (*inotifyInstance, error) {
+	sharedInotify.Lock()
+	defer sharedInotify.Unlock()
+
+	in := sharedInotify.in
+	if in == nil {
+		fd, err := unix.InotifyInit1(unix.IN_CLOEXEC | unix.IN_NONBLOCK)
+		if err != nil {
+			return nil, err
+		}
+		in = &inotifyInstance{
+			f:    os.NewFile(uintptr(fd), "inotify"),
+			refs: 0,
+			mu:   sync.Mutex{},
+			subs: make(map[int32][]*inotify),
+			err:  nil,
+		}
+		sharedInotify.in = in
+		go in.readEvents()
+	}
+	in.refs++
+	return in, nil
+}
+
+// release removes all watches of w and closes inotify instance if it's
+// no longer used.
+func (in *inotifyInstance) release(w *inotify) {
+	in.mu.Lock()
+	in.control(func(fd int) {
+		if w.dirWD >= 0 {
+			in.unsubscribe(fd, w, w.dirWD)
+		}
+		for _, wd := range w.fileWD {
+			in.unsubscribe(fd, w, wd)
+		}
+	})
+	w.dirWD, w.fileWD, w.released = -1, nil, true
+	in.mu.Unlock()
+
+	sharedInotify.Lock()
+	defer sharedInotify.Unlock()
+	in.refs--
+	if in.refs == 0 {
+		if sharedInotify.in == in {
+			sharedInotify.in = nil
+		}
+		_ = in.f.Close()
+	}
+}
+
+// Changed implements Watcher interface.
+// After w was released it returns a channel which never fires.
+func (w *inotify) Changed(bool, time.Duration) <-chan struct{} {
+	w.in.mu.Lock()
+	defer w.in.mu.Unlock()
+	switch {
+	case w.released:
+		return nil
+	case w.in.err != nil:
+		return after(w.rescan)
+	}
+
+	w.in.control(func(fd int) {
+		if w.dirWD < 0 {
+			wd, err := unix.InotifyAddWatch(fd, w.dir, inotifyDirMask|unix.IN_MASK_ADD)
+			if err == nil {
+				w.dirWD = int32(wd) //nolint:gosec // Watch descriptors are small.
+				w.in.subscribe(w, w.dirWD)
+			}
+		}
+
+		// Watch is added to the file which path points to right now.
+		// There is a race between opening a file and adding a watch, so we
+		// report a change after adding a new watch to force one more check.
+		wd, err := unix.InotifyAddWatch(fd, w.path, inotifyFileMask|unix.IN_MASK_ADD)
+		if err == nil && !slices.Contains(w.fileWD, int32(wd)) { //nolint:gosec // Watch descriptors are small.
+			w.fileWD = append(w.fileWD, int32(wd)) //nolint:gosec // Watch descriptors are small.
+			w.in.subscribe(w, int32(wd))           //nolint:gosec // Watch descriptors are small.
+			if len(w.fileWD) > inotifyMaxFiles {
+				w.in.unsubscribe(fd, w, w.fileWD[0])
+				w.fileWD = w.fileWD[1:]
+			}
+			w.notify()
+		}
+	})
+	w.timer.Reset(w.rescan)
+	return w.c
+}
+
+// Err implements failingWatcher interface.
+func (w *inotify) Err() error {
+	w.in.mu.Lock()
+	defer w.in.mu.Unlock()
+	return w.in.err
+}
+
+// control calls f with inotify file descriptor unless it's closed.
+func (in *inotifyInstance) control(f func(fd int)) {
+	conn, err := in.f.SyscallConn()
+	if err == nil {
+		_ = conn.Control(func(fd uintptr) { f(int(fd)) }) //nolint:gosec // File descriptors are small.
+	}
+}
+
+// subscribe adds w to watchers notified about events of wd.
+// It must be called with in.mu locked.
+func (in *inotifyInstance) subscribe(w *inotify, wd int32) {
+	if !slices.Contains(in.subs[wd], w) {
+		in.subs[wd] = append(in.subs[wd], w)
+	}
+}
+
+// unsubscribe removes w from watchers notified about events of wd and
+// removes watch wd if it has no more watchers.
+// It must be called with in.mu locked.
+func (in *inotifyInstance) unsubscribe(fd int, w *inotify, wd int32) {
+	subs := slices.DeleteFunc(in.subs[wd], func(s *inotify) bool { return s == w })
+	if len(subs) > 0 {
+		in.subs[wd] = subs
+		return
+	}
+	delete(in.subs, wd)
+	_, _ = unix.InotifyRmWatch(fd, uint32(wd)) //nolint:gosec // Watch descriptors are positive.
+}
+
+func (w *inotify) notify() {
+	select {
+	case w.c <- struct{}{}:
+	default:
+	}
+}
+
+func (in *inotifyInstance) readEvents() {
+	buf := make([]byte, 64*(unix.SizeofInotifyEvent+unix.NAME_MAX+1))
+	for {
+		n, err := in.f.Read(buf)
+		if err != nil {
+			if !errors.Is(err, os.ErrClosed) {
+				in.fail(err)
+			}
+			return
+		}
+		in.mu.Lock()
+		for off := 0; off+unix.SizeofInotifyEvent <= n; {
+			ev := (*unix.InotifyEvent)(unsafe.Pointer(&buf[off])) //nolint:gosec // Kernel ABI.
+			name := buf[off+unix.SizeofInotifyEvent : off+unix.SizeofInotifyEvent+int(ev.Len)]
+			off += unix.SizeofInotifyEvent + int(ev.Len)
+			in.dispatch(ev, name)
+		}
+		in.mu.Unlock()
+	}
+}
+
+// dispatch notifies watchers interested in event.
+// It must be called with in.mu locked.
+func (in *inotifyInstance) dispatch(ev *unix.InotifyEvent, name []byte) {
+	switch {
+	case ev.Mask&unix.IN_Q_OVERFLOW != 0:
+		for _, subs := range in.subs {
+			for _, w := range subs {
+				w.notify()
+			}
+		}
+	case ev.Mask&unix.IN_IGNORED != 0: // Watch was removed.
+		for _, w := range in.subs[ev.Wd] {
+			if w.dirWD == ev.Wd {
+				w.dirWD = -1
+			}
+			w.fileWD = slices.DeleteFunc(w.fileWD, func(wd int32) bool { return wd == ev.Wd })
+			w.notify()
+		}
+		delete(in.subs, ev.Wd)
+	default:
+		for _, w := range in.subs[ev.Wd] {
+			if ev.Wd != w.dirWD || w.isName(name) {
+				w.notify()
+			}
+		}
+	}
+}
+
+// fail switches all watchers using in to polling. Watchers created
+// after this will use a new inotify instance.
+func (in *inotifyInstance) fail(err error) {
+	sharedInotify.Lock()
+	if sharedInotify.in == in {
+		sharedInotify.in = nil
+	}
+	sharedInotify.Unlock()
+
+	in.mu.Lock()
+	defer in.mu.Unlock()
+	in.err = err
+	for _, subs := range in.subs {
+		for _, w := range subs {
+			w.notify()
+		}
+	}
+}
+
+// isName reports whether NUL-padded name is the base name of path.
+func (w *inotify) isName(name []byte) bool {
+	for len(name) > 0 && name[len(name)-1] == 0 {
+		name = name[:len(name)-1]
+	}
+	return string(name) == w.name
+}
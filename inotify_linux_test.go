@@ -0,0 +1,62 @@
+package tail //nolint:testpackage // TODO
+
+import (
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/powerman/check"
+)
+
+func TestInotifyShared(tt *testing.T) {
+	tt.Parallel()
+	t := check.Must(tt)
+	tail := newTestTailInotify(t)
+
+	tail.Run(Inotify(time.Hour))
+	ctx, cancel := context.WithCancel(t.Context())
+	other := Follow(ctx, LoggerFunc(t.Logf), tail.path,
+		PollDelay(pollDelay), PollTimeout(pollTimeout), Inotify(time.Hour))
+	t.Equal(other.watcher.(*inotify).in, tail.watcher.(*inotify).in)
+
+	tail.Write("new1\n")
+	tail.Want(pollDelay/2, "new1\n", nil)
+	buf := make([]byte, 8)
+	n, err := other.Read(buf)
+	t.Nil(err)
+	t.Equal(string(buf[:n]), "new1\n")
+
+	cancel()
+	_, err = other.Read(buf)
+	t.Err(err, io.EOF)
+	time.Sleep(pollDelay / 4)
+	tail.Write("new2\n")
+	tail.Want(pollDelay/2, "new2\n", nil)
+}
+
+func TestInotifyReleased(tt *testing.T) {
+	tt.Parallel()
+	t := check.Must(tt)
+	path := filepath.Join(t.TempDir(), "log")
+	t.Nil(os.WriteFile(path, nil, 0o600))
+
+	ctx, cancel := context.WithCancel(t.Context())
+	watcher, err := newInotify(ctx, path, time.Hour)
+	t.Nil(err)
+	w := watcher.(*inotify)
+	t.NotNil(w.Changed(false, 0))
+	cancel()
+	time.Sleep(pollDelay / 4)
+
+	t.Nil(w.Changed(false, 0))
+	w.in.mu.Lock()
+	defer w.in.mu.Unlock()
+	t.Equal(w.dirWD, int32(-1))
+	t.Len(w.fileWD, 0)
+	for _, subs := range w.in.subs {
+		t.NotContains(subs, w)
+	}
+}
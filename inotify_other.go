@@ -0,0 +1,13 @@
+//go:build !linux
+
+package tail
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+func newInotify(context.Context, string, time.Duration) (Watcher, error) {
+	return nil, errors.ErrUnsupported
+}
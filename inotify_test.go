@@ -0,0 +1,86 @@
+package tail //nolint:testpackage // TODO
+
+import (
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/powerman/check"
+)
+
+func newTestTailInotify(t *check.TB) *testTail {
+	t.Helper()
+	if runtime.GOOS != "linux" {
+		t.Skip("inotify is supported only on Linux")
+	}
+	return newTestTail(t)
+}
+
+func TestInotifyNotExistsGrow(tt *testing.T) {
+	tt.Parallel()
+	t := check.Must(tt)
+	tail := newTestTailInotify(t)
+
+	tail.Remove()
+	tail.Run(Inotify(time.Hour))
+
+	time.Sleep(pollDelay / 2)
+	tail.Create()
+	tail.Write("new1.1\nnew1.2\n")
+	tail.Want(pollDelay/2, "new1.1\nnew1.2\n", nil)
+	tail.Want(pollTimeout+pollDelay/2, "", nil)
+}
+
+func TestInotifyGrow(tt *testing.T) {
+	tt.Parallel()
+	t := check.Must(tt)
+	tail := newTestTailInotify(t)
+
+	tail.Write("old1\n")
+	tail.Run(Inotify(time.Hour))
+
+	tail.Write("new1\n")
+	tail.Want(pollDelay/2, "new1\n", nil)
+	tail.Truncate()
+	tail.Write("new2\n")
+	tail.Want(pollDelay/2, "new2\n", nil)
+}
+
+func TestInotifyRotate(tt *testing.T) {
+	tt.Parallel()
+	t := check.Must(tt)
+	tail := newTestTailInotify(t)
+
+	tail.Run(Inotify(time.Hour))
+
+	tail.Write("old1\n")
+	tail.Want(pollDelay/2, "old1\n", nil)
+	f := tail.f
+	tail.Rename()
+	_, err := f.WriteString("old2\n")
+	t.Nil(err)
+	tail.Want(pollDelay/2, "old2\n", nil)
+	_, err = f.WriteString("old3\n")
+	t.Nil(err)
+	tail.Create()
+	tail.Write("new1\n")
+	tail.Want(pollDelay/2, "old3\nnew1\n", nil)
+	tail.Write("new2\n")
+	tail.Want(pollDelay/2, "new2\n", nil)
+}
+
+func TestInotifySymlink(tt *testing.T) {
+	tt.Parallel()
+	t := check.Must(tt)
+	tail := newTestTailInotify(t)
+
+	tail.CreateSymlink()
+	tail.Run(Inotify(pollDelay))
+
+	tail.Write("old1\n")
+	tail.Want(pollDelay/2, "old1\n", nil)
+	tail.Rename()
+	tail.Create()
+	tail.Write("new1\n")
+	tail.Want(pollDelay*3/2, "new1\n", nil)
+}
@@ -0,0 +1,60 @@
+package tail
+
+import (
+	"maps"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// LatestBy defines how [Latest] chooses the latest file.
+type LatestBy int
+
+// Latest file choosing modes.
+const (
+	// LatestByName chooses lexically greatest path.
+	LatestByName LatestBy = iota + 1
+	// LatestByModTime chooses most recently modified file.
+	LatestByModTime
+)
+
+// resolve returns path to open: path given to Follow or, in [Latest]
+// mode, the latest file matching it (excluding already followed files).
+//
+// In [Latest] mode path is rescanned at most once per [PollDelay].
+func (t *Tail) resolve() string {
+	if t.latest == 0 {
+		return t.path
+	}
+	if time.Since(t.resolvedAt) < t.pollDelay {
+		return t.resolved
+	}
+	t.resolvedAt = time.Now()
+
+	paths, _ := filepath.Glob(t.path) // The only possible error is ErrBadPattern.
+	matched := make(map[string]bool, len(paths))
+	latest := t.path
+	var latestModTime time.Time
+	for _, path := range paths { // Paths are sorted by name.
+		matched[path] = true
+		if t.seen[path] {
+			continue
+		}
+		if t.latest == LatestByName {
+			latest = path
+			continue
+		}
+		fi, err := os.Stat(path)
+		if err == nil && !fi.ModTime().Before(latestModTime) {
+			latest, latestModTime = path, fi.ModTime()
+		}
+	}
+	maps.DeleteFunc(t.seen, func(path string, _ bool) bool { return !matched[path] })
+	t.resolved = latest
+	return latest
+}
+
+// detached reports whether path no longer points to the file being read.
+func (t *Tail) detached() bool {
+	return t.f.Detached() || t.resolve() != t.f.path
+}
@@ -0,0 +1,79 @@
+package tail //nolint:testpackage // TODO
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/powerman/check"
+)
+
+func TestLatest(tt *testing.T) {
+	tt.Parallel()
+	t := check.Must(tt)
+	dir := t.TempDir()
+	path1 := filepath.Join(dir, "app-2026-10-15.log")
+	path2 := filepath.Join(dir, "app-2026-10-16.log")
+	f1, err := createFile(path1)
+	t.Nil(err)
+	defer func() { t.Nil(f1.Close()) }()
+	_, err = f1.WriteString("old\n")
+	t.Nil(err)
+
+	paths := make(map[EventType]string)
+	tail := startTestTail(t, filepath.Join(dir, "app-*.log"),
+		Latest(LatestByName), Events(func(ev Event) { paths[ev.Type] = ev.Path }))
+
+	_, err = f1.WriteString("1\n")
+	t.Nil(err)
+	tail.WantChunkPath(path1, "1\n")
+
+	f2, err := createFile(path2)
+	t.Nil(err)
+	defer func() { t.Nil(f2.Close()) }()
+	_, err = f2.WriteString("3\n")
+	t.Nil(err)
+	_, err = f1.WriteString("2\n")
+	t.Nil(err)
+	tail.WantChunkPath(path1, "2\n")
+	tail.WantChunkPath(path2, "3\n")
+	t.Equal(paths[EventDetached], path1)
+	t.Equal(paths[EventReplaced], path2)
+	t.Equal(paths[EventSwitched], path2)
+
+	t.Nil(os.Remove(path2)) // Must not return to already followed file.
+	_, err = f1.WriteString("lost\n")
+	t.Nil(err)
+	time.Sleep(pollDelay * 2)
+	_, err = f2.WriteString("4\n")
+	t.Nil(err)
+	tail.WantChunkPath(path2, "4\n")
+}
+
+func TestLatestByModTime(tt *testing.T) {
+	tt.Parallel()
+	t := check.Must(tt)
+	dir := t.TempDir()
+	path1 := filepath.Join(dir, "b.log")
+	path2 := filepath.Join(dir, "a.log")
+	f1, err := createFile(path1)
+	t.Nil(err)
+	defer func() { t.Nil(f1.Close()) }()
+	f2, err := createFile(path2)
+	t.Nil(err)
+	defer func() { t.Nil(f2.Close()) }()
+	old := time.Now().Add(-time.Hour)
+	t.Nil(os.Chtimes(path2, old, old))
+
+	tail := startTestTail(t, filepath.Join(dir, "*.log"), Latest(LatestByModTime), Whence(io.SeekStart))
+
+	_, err = f1.WriteString("1\n")
+	t.Nil(err)
+	tail.WantChunkPath(path1, "1\n")
+	time.Sleep(pollDelay) // Ensure different mtime.
+	_, err = f2.WriteString("2\n")
+	t.Nil(err)
+	tail.WantChunkPath(path2, "2\n")
+}
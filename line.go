@@ -0,0 +1,60 @@
+package tail
+
+import "bytes"
+
+// LineReader reads full lines of text from Tail.
+//
+// It never joins data from different files (e.g. when rotated file ends
+// with unterminated line) or data before and after file truncation:
+// such unterminated line is returned as a separate line.
+type LineReader struct {
+	r *RecordReader
+}
+
+// NewLineReader returns LineReader which reads from t.
+//
+// Data must not be read from t directly while it is used by LineReader.
+func NewLineReader(t *Tail) *LineReader {
+	return &LineReader{
+		r: NewRecordReader(t, scanLine),
+	}
+}
+
+// SetMaxLineSize sets the maximum size of a line (including trailing
+// newline), [DefaultMaxRecordSize] is used by default. Non-positive size
+// is ignored. Longer line is dropped and ReadLine returns
+// [ErrRecordTooLong] instead of it.
+//
+// SetMaxLineSize must not be called after ReadLine.
+func (r *LineReader) SetMaxLineSize(size int) {
+	r.r.SetMaxRecordSize(size)
+}
+
+// ReadLine returns next line including trailing newline.
+//
+// If Tail returns an error (e.g. because file is inaccessible for
+// [PollTimeout]) then ReadLine returns this error and keep partial line
+// buffered, following ReadLine will continue reading same line.
+//
+// Line without trailing newline is returned only when Tail continues
+// reading from another file (or from the beginning of truncated file) or
+// after Tail returns [io.EOF], in the latter case following ReadLine
+// returns [io.EOF].
+//
+// Returned line is valid only until the next call to ReadLine.
+//
+// ReadLine must not be called from simultaneous goroutines.
+func (r *LineReader) ReadLine() ([]byte, error) {
+	return r.r.ReadRecord()
+}
+
+// scanLine is a split function like [ScanLF] but it keeps trailing "\n".
+func scanLine(data []byte, atEOF bool) (advance int, token []byte, err error) {
+	if i := bytes.IndexByte(data, '\n'); i >= 0 {
+		return i + 1, data[:i+1], nil
+	}
+	if atEOF && len(data) > 0 {
+		return len(data), data, nil
+	}
+	return 0, nil, nil
+}
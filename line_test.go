@@ -0,0 +1,77 @@
+package tail //nolint:testpackage // TODO
+
+import (
+	"io"
+	"strings"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/powerman/check"
+)
+
+func TestLineReader(tt *testing.T) {
+	tt.Parallel()
+	t := check.Must(tt)
+	tail := newTestTail(t)
+
+	tail.Write("old\nol")
+	tail.Start()
+
+	tail.Write("d\nnew1\nnew2\nne")
+	tail.WantLine("d\n", nil)
+	tail.WantLine("new1\n", nil)
+	tail.Write("w3\n" + strings.Repeat("x", minReadSize*3) + "\n")
+	tail.WantLine("new2\n", nil)
+	tail.WantLine("new3\n", nil)
+	tail.WantLine(strings.Repeat("x", minReadSize*3)+"\n", nil)
+
+	tail.Write("new4.1")
+	tail.Remove()
+	tail.WantLine("", syscall.ENOENT)
+	tail.Write("new4.2\nnew5")
+	tail.WantLine("new4.1new4.2\n", nil)
+
+	go func() {
+		time.Sleep(pollDelay * 2)
+		tail.Cancel()
+	}()
+	tail.WantLine("new5", nil)
+	tail.WantLine("", io.EOF)
+	tail.WantLine("", io.EOF)
+}
+
+func TestLineReaderTooLong(tt *testing.T) {
+	tt.Parallel()
+	t := check.Must(tt)
+	tail := newTestTail(t)
+
+	tail.Start()
+	tail.lines.SetMaxLineSize(4)
+	tail.Write("abcdefghij\nxy\n")
+	tail.WantLine("", ErrRecordTooLong)
+	tail.WantLine("xy\n", nil)
+}
+
+func TestLineReaderRotate(tt *testing.T) {
+	tt.Parallel()
+	t := check.Must(tt)
+	tail := newTestTail(t)
+
+	tail.Start()
+	tail.Write("old1\nold2")
+	tail.WantLine("old1\n", nil)
+	tail.Truncate()
+	go func() {
+		time.Sleep(pollDelay * 2)
+		tail.Write("new1\nnew2")
+	}()
+	tail.WantLine("old2", nil)
+	tail.WantLine("new1\n", nil)
+
+	tail.Rename()
+	tail.Create()
+	tail.Write("new3\n")
+	tail.WantLine("new2", nil)
+	tail.WantLine("new3\n", nil)
+}
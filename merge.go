@@ -0,0 +1,128 @@
+package tail
+
+import (
+	"bytes"
+	"container/heap"
+	"context"
+	"errors"
+	"io"
+	"time"
+)
+
+// Merge reads lines of text from several Tails ordered by timestamp
+// embedded in lines.
+//
+// Lines are buffered for a delay to wait for lines with earlier timestamp
+// from slower Tails, so lines delayed for more than delay may be returned
+// out of order.
+type Merge struct {
+	in      *fanIn[Line]
+	extract TimestampFunc
+	delay   time.Duration
+	last    map[string]time.Time // Last timestamp for each path.
+	buf     mergeHeap
+	seq     uint64
+	eof     bool
+}
+
+// NewMerge returns Merge which reads lines from tails until ctx is done
+// and orders them by timestamp returned by extract, buffering lines for
+// delay. Lines without timestamp get timestamp of previous line from
+// same Tail.
+//
+// Data must not be read from tails directly while they are used by Merge.
+func NewMerge(ctx context.Context, extract TimestampFunc, delay time.Duration, tails ...*Tail) *Merge {
+	m := &Merge{
+		in:      newFanIn[Line](ctx),
+		extract: extract,
+		delay:   delay,
+		last:    make(map[string]time.Time),
+		buf:     nil,
+		seq:     0,
+		eof:     false,
+	}
+	for _, t := range tails {
+		m.in.spawn(followLines(t))
+	}
+	m.in.close()
+	return m
+}
+
+// ReadLine returns next line from any of the Tails.
+//
+// If Tail returns an error except [io.EOF] then ReadLine returns it
+// together with Line.Path set to the Tail's path.
+//
+// ReadLine returns [io.EOF] after all the Tails have returned [io.EOF]
+// (or ctx is done) and all buffered lines were returned.
+//
+// ReadLine must not be called from simultaneous goroutines.
+func (m *Merge) ReadLine() (Line, error) {
+	for {
+		var timeoutc <-chan time.Time
+		if m.buf.Len() > 0 {
+			wait := m.delay - time.Since(m.buf[0].added)
+			if wait <= 0 || m.eof {
+				return heap.Pop(&m.buf).(mergeLine).Line, nil //nolint:forcetypeassert // Always mergeLine.
+			}
+			timeoutc = time.After(wait)
+		}
+		if m.eof {
+			return Line{Path: "", Data: nil}, io.EOF
+		}
+
+		line, err := m.in.recv(timeoutc)
+		switch {
+		case errors.Is(err, errFanInTimeout):
+		case errors.Is(err, io.EOF):
+			m.eof = true
+		case err != nil:
+			return line, err
+		default:
+			line.Data = bytes.Clone(line.Data)
+			m.add(line)
+		}
+	}
+}
+
+func (m *Merge) add(line Line) {
+	ts, ok := m.extract(bytes.TrimRight(line.Data, "\r\n"))
+	if ok {
+		m.last[line.Path] = ts
+	} else {
+		ts = m.last[line.Path]
+	}
+	heap.Push(&m.buf, mergeLine{Line: line, ts: ts, seq: m.seq, added: time.Now()})
+	m.seq++
+}
+
+type mergeLine struct {
+	Line
+
+	ts    time.Time
+	seq   uint64 // Keeps order of lines with same timestamp.
+	added time.Time
+}
+
+// mergeHeap implements [heap.Interface].
+type mergeHeap []mergeLine
+
+func (h mergeHeap) Len() int { return len(h) }
+
+func (h mergeHeap) Less(i, j int) bool {
+	if c := h[i].ts.Compare(h[j].ts); c != 0 {
+		return c < 0
+	}
+	return h[i].seq < h[j].seq
+}
+
+func (h mergeHeap) Swap(i, j int) { h[i], h[j] = h[j], h[i] }
+
+func (h *mergeHeap) Push(x any) { *h = append(*h, x.(mergeLine)) } //nolint:forcetypeassert // Always mergeLine.
+
+func (h *mergeHeap) Pop() any {
+	old := *h
+	x := old[len(old)-1]
+	*h = old[:len(old)-1]
+	return x
+}
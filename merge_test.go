@@ -0,0 +1,54 @@
+package tail //nolint:testpackage // TODO
+
+import (
+	"io"
+	"testing"
+	"time"
+
+	"github.com/powerman/check"
+)
+
+func TestMerge(tt *testing.T) {
+	tt.Parallel()
+	t := check.Must(tt)
+	tailA := newTestTail(t)
+	tailB := newTestTail(t)
+	extract := func(line []byte) (time.Time, bool) {
+		ts, err := time.Parse(time.TimeOnly, string(line[:min(len(line), len(time.TimeOnly))]))
+		return ts, err == nil
+	}
+
+	tailA.Start()
+	tailB.Start()
+	delay := pollDelay * 5
+	m := NewMerge(t.Context(), extract, delay, tailA.Tail, tailB.Tail)
+	want := func(wantPath, wantData string) {
+		t.Helper()
+		line, err := m.ReadLine()
+		t.Nil(err)
+		t.Equal(line.Path, wantPath)
+		t.Equal(string(line.Data), wantData)
+	}
+
+	tailA.Write("10:00:02 a1\n10:00:04 a2\n  a2 continued\n")
+	time.Sleep(pollDelay * 2)
+	tailB.Write("10:00:01 b1\n10:00:03 b2\n10:00:05 b3\n")
+	start := time.Now()
+	want(tailB.path, "10:00:01 b1\n")
+	t.Greater(time.Since(start), delay/2)
+	want(tailA.path, "10:00:02 a1\n")
+	want(tailB.path, "10:00:03 b2\n")
+	want(tailA.path, "10:00:04 a2\n")
+	want(tailA.path, "  a2 continued\n")
+	want(tailB.path, "10:00:05 b3\n")
+
+	tailA.Write("10:00:06 a3\n")
+	want(tailA.path, "10:00:06 a3\n")
+	tailB.Write("10:00:05 late\n")
+	want(tailB.path, "10:00:05 late\n")
+
+	tailA.Cancel()
+	tailB.Cancel()
+	_, err := m.ReadLine()
+	t.Err(err, io.EOF)
+}
@@ -0,0 +1,86 @@
+package tail
+
+import (
+	"context"
+)
+
+// Line is a line of text read by Multi together with its source.
+type Line struct {
+	// Path given to Follow.
+	Path string
+	// Data with trailing newline (if any).
+	Data []byte
+}
+
+// Multi reads lines of text from several Tails, like `tail -F path...`.
+type Multi struct {
+	headers bool
+	in      *fanIn[Line]
+	path    string // Path of last line returned by Read.
+	out     []byte // Buffer for data returned by Read.
+	buf     []byte // Part of out not yet returned by Read.
+}
+
+// NewMulti returns Multi which reads lines from tails until ctx is done.
+//
+// If headers is true then Read outputs `==> path <==` header (like
+// `tail`) before lines from another path.
+//
+// Data must not be read from tails directly while they are used by Multi.
+func NewMulti(ctx context.Context, headers bool, tails ...*Tail) *Multi {
+	m := &Multi{
+		headers: headers,
+		in:      newFanIn[Line](ctx),
+		path:    "",
+		out:     nil,
+		buf:     nil,
+	}
+	for _, t := range tails {
+		m.in.spawn(followLines(t))
+	}
+	m.in.close()
+	return m
+}
+
+// ReadLine returns next line from any of the Tails. Lines from different
+// Tails are never joined, see [LineReader.ReadLine] for details.
+//
+// If Tail returns an error except [io.EOF] then ReadLine returns it
+// together with Line.Path set to the Tail's path.
+//
+// ReadLine returns [io.EOF] after all the Tails have returned [io.EOF]
+// or ctx is done.
+//
+// Returned Line.Data is valid only until the next call to ReadLine.
+//
+// ReadLine must not be called from simultaneous goroutines.
+func (m *Multi) ReadLine() (Line, error) {
+	return m.in.recv(nil)
+}
+
+// Read implements [io.Reader] returning lines from all the Tails (with
+// headers if enabled by NewMulti).
+//
+// Read must not be called from simultaneous goroutines and must not be
+// used together with ReadLine.
+func (m *Multi) Read(p []byte) (int, error) {
+	if len(m.buf) == 0 && len(p) > 0 {
+		line, err := m.ReadLine()
+		if err != nil {
+			return 0, err
+		}
+		m.out = m.out[:0]
+		if m.headers && line.Path != m.path {
+			if m.path != "" {
+				m.out = append(m.out, '\n')
+			}
+			m.out = append(m.out, "==> "+line.Path+" <==\n"...)
+		}
+		m.path = line.Path
+		m.out = append(m.out, line.Data...)
+		m.buf = m.out
+	}
+	n := copy(p, m.buf)
+	m.buf = m.buf[n:]
+	return n, nil
+}
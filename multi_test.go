@@ -0,0 +1,88 @@
+package tail //nolint:testpackage // TODO
+
+import (
+	"context"
+	"io"
+	"testing"
+
+	"github.com/powerman/check"
+)
+
+func TestMulti(tt *testing.T) {
+	tt.Parallel()
+	t := check.Must(tt)
+	tailA := newTestTail(t)
+	tailB := newTestTail(t)
+
+	tailA.Start()
+	tailB.Start()
+	m := NewMulti(t.Context(), false, tailA.Tail, tailB.Tail)
+	want := func(wantPath, wantData string) {
+		t.Helper()
+		line, err := m.ReadLine()
+		t.Nil(err)
+		t.Equal(line.Path, wantPath)
+		t.Equal(string(line.Data), wantData)
+	}
+
+	tailA.Write("a1\na")
+	want(tailA.path, "a1\n")
+	tailB.Write("b1\n")
+	want(tailB.path, "b1\n")
+	tailA.Write("2\n")
+	want(tailA.path, "a2\n")
+
+	tailA.Cancel()
+	tailB.Cancel()
+	line, err := m.ReadLine()
+	t.Err(err, io.EOF)
+	t.DeepEqual(line, Line{})
+}
+
+func TestMultiHeaders(tt *testing.T) {
+	tt.Parallel()
+	t := check.Must(tt)
+	tailA := newTestTail(t)
+	tailB := newTestTail(t)
+
+	tailA.Start()
+	tailB.Start()
+	m := NewMulti(t.Context(), true, tailA.Tail, tailB.Tail)
+	p := make([]byte, 8)
+	want := func(wantData string) {
+		t.Helper()
+		var data string
+		for len(data) < len(wantData) {
+			n, err := m.Read(p)
+			t.Nil(err)
+			data += string(p[:n])
+		}
+		t.Equal(data, wantData)
+	}
+
+	tailA.Write("a1\n")
+	want("==> " + tailA.path + " <==\na1\n")
+	tailA.Write("a2\n")
+	want("a2\n")
+	tailB.Write("b1\n")
+	want("\n==> " + tailB.path + " <==\nb1\n")
+}
+
+func TestMultiCancel(tt *testing.T) {
+	tt.Parallel()
+	t := check.Must(tt)
+	tail := newTestTail(t)
+
+	tail.Start()
+	ctx, cancel := context.WithCancel(t.Context())
+	m := NewMulti(ctx, false, tail.Tail)
+	tail.Write("1\n2\n")
+	line, err := m.ReadLine()
+	t.Nil(err)
+	t.Equal(string(line.Data), "1\n")
+
+	cancel()
+	line, err = m.ReadLine()
+	t.Err(err, io.EOF)
+	t.DeepEqual(line, Line{})
+}
@@ -0,0 +1,165 @@
+package tail
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"regexp"
+	"slices"
+	"time"
+)
+
+// MultilineConfig defines how lines are grouped into multiline records.
+//
+// Either Start or Continue must be set.
+type MultilineConfig struct {
+	// Start matches the first line of a record, all following lines which
+	// does not match Start belongs to the same record.
+	Start *regexp.Regexp
+	// Continue matches continuation lines, all lines which does not
+	// match Continue starts a new record. Used only if Start is nil.
+	Continue *regexp.Regexp
+	// MaxLines limits amount of lines in a record (0 means no limit).
+	MaxLines int
+	// MaxBytes limits size of a record (0 means no limit). A record with
+	// a single line may be larger than MaxBytes.
+	MaxBytes int
+	// FlushTimeout is how long to wait for continuation lines before
+	// returning a record (0 means wait for the next record start).
+	FlushTimeout time.Duration
+}
+
+// MultilineReader reads multiline records (e.g. stack traces) from
+// LineReader.
+//
+// It reads lines in a background goroutine which exits after LineReader
+// returns [io.EOF] or after Close.
+type MultilineReader struct {
+	r      *LineReader
+	cfg    MultilineConfig
+	in     *fanIn[[]byte]
+	cancel context.CancelFunc
+	rec    []byte
+	lines  int
+	eof    bool
+}
+
+// NewMultilineReader returns MultilineReader which reads lines from r and
+// groups them according to cfg.
+//
+// It returns an error if cfg is invalid.
+//
+// Lines must not be read from r directly while it is used by
+// MultilineReader.
+func NewMultilineReader(r *LineReader, cfg MultilineConfig) (*MultilineReader, error) {
+	switch {
+	case cfg.Start == nil && cfg.Continue == nil:
+		return nil, errors.New("multiline: either Start or Continue must be set")
+	case cfg.MaxLines < 0 || cfg.MaxBytes < 0 || cfg.FlushTimeout < 0:
+		return nil, errors.New("multiline: limits must not be negative")
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	mr := &MultilineReader{
+		r:      r,
+		cfg:    cfg,
+		in:     newFanIn[[]byte](ctx),
+		cancel: cancel,
+		rec:    nil,
+		lines:  0,
+		eof:    false,
+	}
+	mr.in.spawn(mr.readLines)
+	mr.in.close()
+	return mr, nil
+}
+
+// Close stops reading lines from LineReader. Following ReadRecord returns
+// current record (if any) and then [io.EOF].
+//
+// Close may be called from any goroutine. It does not stop Tail used by
+// LineReader, but background goroutine may be blocked in Tail's Read
+// until Tail returns.
+func (r *MultilineReader) Close() {
+	r.cancel()
+}
+
+// ReadRecord returns next record. Returned record contains all lines of
+// a record including their trailing newlines.
+//
+// Record is returned when the next record starts, when it reaches
+// MaxLines or MaxBytes limits, after FlushTimeout since the last line of
+// the record or when LineReader returns [io.EOF].
+//
+// If LineReader returns an error except [io.EOF] then ReadRecord returns
+// this error and keep current record, following ReadRecord will continue
+// reading same record. After LineReader returns [io.EOF] ReadRecord
+// returns current record (if any) and then [io.EOF].
+//
+// ReadRecord must not be called from simultaneous goroutines.
+func (r *MultilineReader) ReadRecord() ([]byte, error) {
+	var timeoutc <-chan time.Time
+	for {
+		switch {
+		case r.eof:
+			return r.flush(nil), io.EOF
+		case r.cfg.MaxLines > 0 && r.lines >= r.cfg.MaxLines:
+			return r.flush(nil), nil
+		}
+		if r.lines > 0 && r.cfg.FlushTimeout > 0 && timeoutc == nil {
+			timeoutc = time.After(r.cfg.FlushTimeout)
+		}
+
+		line, err := r.in.recv(timeoutc)
+		if !errors.Is(err, errFanInTimeout) {
+			timeoutc = nil
+		}
+
+		switch {
+		case errors.Is(err, errFanInTimeout):
+			return r.flush(nil), nil
+		case errors.Is(err, io.EOF):
+			r.eof = true
+			if r.lines > 0 {
+				return r.flush(nil), nil
+			}
+		case err != nil:
+			return nil, err
+		case r.lines > 0 && r.starts(line):
+			return r.flush(line), nil
+		case r.lines > 0 && r.cfg.MaxBytes > 0 && len(r.rec)+len(line) > r.cfg.MaxBytes:
+			return r.flush(line), nil
+		default:
+			r.rec = append(r.rec, line...)
+			r.lines++
+		}
+	}
+}
+
+func (r *MultilineReader) readLines(send func([]byte, error) bool) {
+	for {
+		line, err := r.r.ReadLine()
+		if errors.Is(err, io.EOF) || !send(line, err) {
+			return
+		}
+	}
+}
+
+// starts reports whether line starts a new record.
+func (r *MultilineReader) starts(line []byte) bool {
+	line = bytes.TrimSuffix(line, []byte("\n"))
+	if r.cfg.Start != nil {
+		return r.cfg.Start.Match(line)
+	}
+	return !r.cfg.Continue.Match(line)
+}
+
+// flush returns current record and starts a new one with given line.
+func (r *MultilineReader) flush(line []byte) []byte {
+	rec := r.rec
+	r.rec, r.lines = nil, 0
+	if line != nil {
+		r.rec, r.lines = slices.Clone(line), 1
+	}
+	return rec
+}
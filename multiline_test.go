@@ -0,0 +1,100 @@
+package tail //nolint:testpackage // TODO
+
+import (
+	"io"
+	"path/filepath"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/powerman/check"
+)
+
+func TestMultilineReader(tt *testing.T) {
+	tt.Parallel()
+	t := check.Must(tt)
+	newReader := func(cfg MultilineConfig) (*testTail, *MultilineReader) {
+		t.Helper()
+		tail := newTestTail(t)
+		tail.Start()
+		r, err := NewMultilineReader(tail.lines, cfg)
+		t.Nil(err)
+		return tail, r
+	}
+
+	tail, r := newReader(MultilineConfig{
+		Start:        regexp.MustCompile(`^\d`),
+		Continue:     nil,
+		MaxLines:     3,
+		MaxBytes:     0,
+		FlushTimeout: 0,
+	})
+	tail.Write("1 a\n\tat b\n\tat c\n2 d\n3 e\n\tat f\n\tat g\n\tat h\n\tat i\n4 j\n\tat k")
+	tail.WantRecord(r, "1 a\n\tat b\n\tat c\n", nil)
+	tail.WantRecord(r, "2 d\n", nil)
+	tail.WantRecord(r, "3 e\n\tat f\n\tat g\n", nil)
+	tail.WantRecord(r, "\tat h\n\tat i\n", nil)
+	go func() {
+		time.Sleep(pollDelay * 2)
+		tail.Cancel()
+	}()
+	tail.WantRecord(r, "4 j\n\tat k", nil)
+	tail.WantRecord(r, "", io.EOF)
+	tail.WantRecord(r, "", io.EOF)
+
+	tail, r = newReader(MultilineConfig{
+		Start:        nil,
+		Continue:     regexp.MustCompile(`^\s`),
+		MaxLines:     0,
+		MaxBytes:     12,
+		FlushTimeout: pollDelay,
+	})
+	tail.Write("1 a\n\tat b\n\tat c\n2 d\n\tat e\n")
+	tail.WantRecord(r, "1 a\n\tat b\n", nil)
+	tail.WantRecord(r, "\tat c\n", nil)
+	start := time.Now()
+	tail.WantRecord(r, "2 d\n\tat e\n", nil)
+	t.Between(time.Since(start), pollDelay, pollDelay*2)
+	tail.Cancel()
+	tail.WantRecord(r, "", io.EOF)
+}
+
+func TestMultilineReaderInvalid(tt *testing.T) {
+	tt.Parallel()
+	t := check.Must(tt)
+	tail := Follow(t.Context(), LoggerFunc(t.Logf), filepath.Join(t.TempDir(), "log"))
+
+	for _, cfg := range []MultilineConfig{
+		{Start: nil, Continue: nil, MaxLines: 0, MaxBytes: 0, FlushTimeout: 0},
+		{Start: regexp.MustCompile(`^\d`), Continue: nil, MaxLines: -1, MaxBytes: 0, FlushTimeout: 0},
+	} {
+		_, err := NewMultilineReader(NewLineReader(tail), cfg)
+		t.NotNil(err)
+	}
+}
+
+func TestMultilineReaderClose(tt *testing.T) {
+	tt.Parallel()
+	t := check.Must(tt)
+	tail := newTestTail(t)
+
+	tail.Start()
+	defer func() {
+		tail.Cancel()
+		time.Sleep(pollDelay / 2) // Wait for background goroutine to finish Read.
+	}()
+	r, err := NewMultilineReader(tail.lines, MultilineConfig{
+		Start:        regexp.MustCompile(`^\d`),
+		Continue:     nil,
+		MaxLines:     0,
+		MaxBytes:     0,
+		FlushTimeout: 0,
+	})
+	t.Nil(err)
+	tail.Write("1 a\n2 b\n\tat c\n")
+	tail.WantRecord(r, "1 a\n", nil)
+
+	r.Close()
+	tail.WantRecord(r, "2 b\n", nil)
+	tail.WantRecord(r, "", io.EOF)
+}
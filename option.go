@@ -1,6 +1,9 @@
 package tail
 
-import "time"
+import (
+	"log/slog"
+	"time"
+)
 
 // Defaults for corresponding options.
 const (
@@ -18,10 +21,27 @@ type optionFunc func(*Tail)
 func (f optionFunc) apply(t *Tail) { f(t) }
 
 // PollDelay let you change delay between polling to save CPU.
+// It's not used with [Watch] option unless given Watcher fails.
 func PollDelay(d time.Duration) Option {
 	return optionFunc(func(t *Tail) { t.pollDelay = d })
 }
 
+// Inotify let you use inotify on Linux to detect changes instead of
+// polling every [PollDelay]. It falls back to polling every [PollDelay] if
+// inotify is not available. It's a shortcut for
+// Watch(InotifyWatcher(rescan)), see [InotifyWatcher] for details.
+func Inotify(rescan time.Duration) Option {
+	return Watch(InotifyWatcher(rescan))
+}
+
+// Watch let you change how Tail detects changes of followed path.
+// Default is to poll every [PollDelay].
+// If newWatcher returns an error it's logged and Tail falls back to
+// polling every [PollDelay].
+func Watch(newWatcher NewWatcher) Option {
+	return optionFunc(func(t *Tail) { t.newWatcher = newWatcher })
+}
+
 // PollTimeout let you change how long to wait before return error when
 // failed to open or read file.
 func PollTimeout(d time.Duration) Option {
@@ -31,7 +51,146 @@ func PollTimeout(d time.Duration) Option {
 // Whence lets you change where you want to start with tailing the file.
 // Default is [io.SeekEnd], which means start from the end of the file.
 // Only [io.SeekStart] and [io.SeekEnd] are supported.
+// It overrides [Offset], [Lines] and [Since] and vice versa.
 // Affects only the file opened by Follow (if exists), not the next files (opened while Read).
 func Whence(w int) Option {
-	return optionFunc(func(t *Tail) { t.whence = w })
+	return optionFunc(func(t *Tail) { t.start = startWhence(w) })
+}
+
+// Offset lets you start with given byte offset in the file.
+// Only [io.SeekStart] and [io.SeekEnd] are supported as whence.
+// Use negative offset with [io.SeekEnd] to start with last -offset bytes
+// (like `tail -c N`) or positive offset with [io.SeekStart] to skip first
+// offset bytes (`tail -c +N` corresponds to offset N-1).
+// If resulting offset is beyond the end of the file it is logged and
+// tailing begins from the end of the file, if resulting offset is before
+// the beginning of the file tailing begins from the beginning of the file.
+// It overrides [Whence], [Lines] and [Since] and vice versa.
+// Affects only usual file opened by Follow (if exists): FIFO is read
+// from the current position and next files are read from the beginning.
+func Offset(offset int64, whence int) Option {
+	return optionFunc(func(t *Tail) { t.start = startOffset(offset, whence) })
+}
+
+// Lines lets you start with last n lines of the file, like `tail -n N`.
+// Lines are searched by reading the file backwards from the end, so it
+// works fast even for huge files.
+// It overrides [Whence], [Offset] and [Since] and vice versa.
+// Affects only usual file opened by Follow (if exists): FIFO is read
+// from the current position and next files are read from the beginning.
+func Lines(n int) Option {
+	return optionFunc(func(t *Tail) { t.start = startLines(n) })
+}
+
+// Since lets you start with the first line which has a timestamp not
+// before given time.
+// Timestamps are extracted from lines by extract, lines without a
+// timestamp are considered part of the preceding line's record.
+// Line is searched using binary search, so it works fast even for huge
+// files, but lines must be ordered by their timestamps.
+// If there are no such lines tailing begins from the end of the file.
+// It overrides [Whence], [Offset] and [Lines] and vice versa.
+// Affects only usual file opened by Follow (if exists): FIFO is read
+// from the current position and next files are read from the beginning.
+func Since(since time.Time, extract TimestampFunc) Option {
+	return optionFunc(func(t *Tail) { t.start = startSince(since, extract) })
+}
+
+// Resume lets you continue tailing the file from saved position (see
+// [Tail.Position]). If the file opened by Follow is not the one pos belongs
+// to then tailing starts according to whence, like with [Whence] option.
+// With [CatchUp] whence is used only if no rotated file matches pos.
+func Resume(pos Position, whence int) Option {
+	return optionFunc(func(t *Tail) {
+		t.resume = &pos
+		t.start = startWhence(whence)
+	})
+}
+
+// Slog makes Tail log using logger instead of Logger given to Follow.
+// Messages about file rotation are logged at [slog.LevelInfo], about
+// inaccessible file at [slog.LevelWarn] and about read errors at
+// [slog.LevelError], with attributes "path", "inode", "offset" and
+// "error" (when applicable).
+// Use [slog.New] to log using [slog.Handler].
+func Slog(logger *slog.Logger) Option {
+	return optionFunc(func(t *Tail) { t.slog = logger })
+}
+
+// Events lets you receive Tail state changes. Handler is called
+// synchronously by Follow and Read, so it should not block.
+func Events(handler func(Event)) Option {
+	return optionFunc(func(t *Tail) { t.events = handler })
+}
+
+// Latest makes Follow treat path as a pattern (see [filepath.Match]) and
+// follow the latest matching file chosen by given mode. When another file
+// becomes the latest Tail reads current file till the end and continues
+// reading new file from the beginning, like after log rotation. Files
+// which were already followed are not followed again. Pattern is checked
+// for new matching files at most once per [PollDelay].
+//
+// Watcher will get pattern as a path, so use [PollWatcher] or
+// [AdaptivePollWatcher] with this option.
+func Latest(by LatestBy) Option {
+	return optionFunc(func(t *Tail) { t.latest = by })
+}
+
+// CatchUp makes Follow find files rotated from path (e.g. path.1, path.2
+// or path-20261016) and read them, oldest first, before path. Rotated
+// files compressed by gzip, bzip2 or zlib (path.2.gz, path.3.bz2,
+// path.4.zlib) are decompressed. Path is read from the beginning.
+//
+// When used with [Resume] reading starts from the position in the
+// rotated file (or path) matching it. If no file matches it then rotated
+// files are not read and path is read according to Resume's whence.
+// Without Resume CatchUp overrides [Whence], [Offset], [Lines] and [Since].
+func CatchUp() Option {
+	return optionFunc(func(t *Tail) { t.catchUp = true })
+}
+
+// FollowDescriptor makes Tail keep reading the file opened from path
+// forever, even after it was renamed or removed, like `tail -f`.
+// If path was inaccessible then Tail will read the first file opened from
+// path.
+func FollowDescriptor() Option {
+	return optionFunc(func(t *Tail) { t.descriptor = true })
+}
+
+// PID makes Tail stop after process with given pid has exited, like
+// `tail --pid`: Read will return [io.EOF] after reading all data written
+// to the file before process has exited.
+func PID(pid int) Option {
+	return optionFunc(func(t *Tail) { t.pid = pid })
+}
+
+// Drain makes Tail continue reading after cancelling ctx for up to
+// timeout: Read will return data already written to current file and to
+// the new file (if it was already opened after log rotation) and then
+// [io.EOF].
+func Drain(timeout time.Duration) Option {
+	return optionFunc(func(t *Tail) { t.drain = timeout })
+}
+
+// Metrics lets you receive Tail metrics updates.
+func Metrics(sink MetricsSink) Option {
+	return optionFunc(func(t *Tail) { t.sink = sink })
+}
+
+// TruncateMode defines how Tail handles file truncation.
+type TruncateMode int
+
+// Truncation modes.
+const (
+	// TruncateRestart continues reading from the beginning of the file.
+	TruncateRestart TruncateMode = iota
+	// TruncateError makes Read return [ErrTruncated] and then continues
+	// reading from the beginning of the file.
+	TruncateError
+)
+
+// Truncation lets you change how truncated file is handled.
+// Default is [TruncateRestart].
+func Truncation(mode TruncateMode) Option {
+	return optionFunc(func(t *Tail) { t.truncate = mode })
 }
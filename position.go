@@ -0,0 +1,111 @@
+package tail
+
+import (
+	"errors"
+	"hash/fnv"
+	"io"
+)
+
+// FingerprintSize is the maximum amount of bytes at the beginning of the
+// file used to calculate [Position] fingerprint.
+const FingerprintSize = 4096
+
+// ErrNoPosition is returned by [Tail.Position] when file is not opened or
+// is not a usual file.
+var ErrNoPosition = errors.New("position is not available")
+
+// FileID identifies a file within a system (e.g. device and inode on Unix).
+type FileID struct {
+	Dev uint64 `json:"dev"`
+	Ino uint64 `json:"ino"`
+}
+
+// Position describes a place in a file. It can be serialized, saved and
+// used later to continue tailing the same file using [Resume].
+type Position struct {
+	FileID
+
+	// Offset is amount of bytes already read from the file.
+	Offset int64 `json:"offset"`
+	// Fingerprint is a hash of up to [FingerprintSize] bytes at the
+	// beginning of the file (but not after Offset). It is used to detect
+	// reused inode.
+	Fingerprint uint64 `json:"fingerprint"`
+}
+
+// Position returns position in the file which will be used by next Read.
+//
+// It returns [ErrNoPosition] if file is not opened yet or it is not a
+// usual file (e.g. FIFO).
+//
+// Position must not be called simultaneously with Read.
+func (t *Tail) Position() (Position, error) {
+	if !t.f.Opened() || !t.f.Usual() {
+		return Position{}, ErrNoPosition
+	}
+	fp, err := t.f.fingerprint(t.f.offset)
+	if err != nil {
+		return Position{}, unwrap(err)
+	}
+	return Position{
+		FileID:      t.f.id,
+		Offset:      t.f.offset,
+		Fingerprint: fp,
+	}, nil
+}
+
+// Match reports whether pos may belong to usual file f.
+// Compressed file is matched using only fingerprint and length of
+// decompressed data, because position may be saved before file was
+// compressed.
+func (f *trackedFile) Match(pos Position) bool {
+	if f.unzip == nil && (f.id != pos.FileID || f.info.Size() < pos.Offset) {
+		return false
+	}
+	if f.unzip != nil && pos.Offset == 0 { // Empty fingerprint matches any file.
+		return false
+	}
+	if f.unzip != nil && !f.hasOffset(pos.Offset) {
+		return false
+	}
+	fp, err := f.fingerprint(pos.Offset)
+	return err == nil && fp == pos.Fingerprint
+}
+
+// hasOffset reports whether compressed file f has at least offset bytes
+// of decompressed data. Size of compressed file can't be used for this,
+// so it decompresses data up to offset.
+func (f *trackedFile) hasOffset(offset int64) bool {
+	z, err := f.unzip(io.NewSectionReader(f, 0, f.info.Size()))
+	if err != nil {
+		return false
+	}
+	_, err = io.CopyN(io.Discard, z, offset)
+	return err == nil
+}
+
+// fingerprint returns hash of up to [FingerprintSize] bytes at the
+// beginning of f (but not after offset). Hash of full FingerprintSize
+// bytes is cached.
+func (f *trackedFile) fingerprint(offset int64) (uint64, error) {
+	if f.fpDone && offset >= FingerprintSize {
+		return f.fp, nil
+	}
+	var r io.Reader = io.NewSectionReader(f, 0, offset)
+	if f.unzip != nil {
+		var err error
+		r, err = f.unzip(io.NewSectionReader(f, 0, f.info.Size()))
+		if err != nil {
+			return 0, err
+		}
+	}
+	h := fnv.New64a()
+	n, err := io.Copy(h, io.LimitReader(r, min(offset, FingerprintSize)))
+	if err != nil {
+		return 0, err
+	}
+	if n == FingerprintSize {
+		f.fp, f.fpDone = h.Sum64(), true
+	}
+	return h.Sum64(), nil
+}
@@ -0,0 +1,68 @@
+package tail //nolint:testpackage // TODO
+
+import (
+	"io"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/powerman/check"
+)
+
+func TestPosition(tt *testing.T) {
+	tt.Parallel()
+	t := check.Must(tt)
+	tail := newTestTail(t)
+
+	tail.Write("old1\nold2\n")
+	tail.Start(Whence(io.SeekStart))
+	tail.WantChunk("old1\n", nil)
+	pos, err := tail.Position()
+	t.Nil(err)
+	t.Equal(pos.Offset, int64(5))
+
+	tail.Write("new1\n")
+	startTestTail(t, tail.path, Resume(pos, io.SeekEnd)).WantChunk("old2\n", nil)
+
+	pos.Fingerprint++
+	dst := startTestTail(t, tail.path, Resume(pos, io.SeekEnd))
+	tail.Write("new2\n")
+	dst.WantChunk("new2\n", nil)
+
+	pos.Fingerprint--
+	tail.Remove()
+	tail.Create()
+	tail.Write("old1\nold2\n")
+	startTestTail(t, tail.path, Resume(pos, io.SeekStart)).WantChunk("old1\n", nil)
+}
+
+func TestPositionFingerprintCached(tt *testing.T) {
+	tt.Parallel()
+	t := check.Must(tt)
+	tail := newTestTail(t)
+
+	tail.Write(strings.Repeat("x", FingerprintSize*2))
+	tail.Start(Whence(io.SeekStart))
+	tail.WantChunk(strings.Repeat("x", FingerprintSize-1), nil)
+	pos1, err := tail.Position()
+	t.Nil(err)
+	t.False(tail.Tail.f.fpDone)
+	tail.WantChunk("x", nil)
+	pos2, err := tail.Position()
+	t.Nil(err)
+	t.True(tail.Tail.f.fpDone)
+	t.NotEqual(pos2.Fingerprint, pos1.Fingerprint)
+	tail.WantChunk(strings.Repeat("x", FingerprintSize), nil)
+	pos3, err := tail.Position()
+	t.Nil(err)
+	t.Equal(pos3.Fingerprint, pos2.Fingerprint)
+}
+
+func TestPositionNotAvailable(tt *testing.T) {
+	tt.Parallel()
+	t := check.Must(tt)
+
+	tail := Follow(t.Context(), LoggerFunc(t.Logf), filepath.Join(t.TempDir(), "log"))
+	_, err := tail.Position()
+	t.Err(err, ErrNoPosition)
+}
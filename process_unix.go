@@ -0,0 +1,15 @@
+//go:build !windows
+
+package tail
+
+import (
+	"errors"
+
+	"golang.org/x/sys/unix"
+)
+
+// processExists reports whether process with given pid is running.
+func processExists(pid int) bool {
+	err := unix.Kill(pid, 0)
+	return err == nil || errors.Is(err, unix.EPERM)
+}
@@ -0,0 +1,23 @@
+//go:build windows
+
+package tail
+
+import (
+	"errors"
+
+	"golang.org/x/sys/windows"
+)
+
+const stillActive = 259 // Exit code of running process (STILL_ACTIVE).
+
+// processExists reports whether process with given pid is running.
+func processExists(pid int) bool {
+	h, err := windows.OpenProcess(windows.PROCESS_QUERY_LIMITED_INFORMATION, false, uint32(pid)) //nolint:gosec // PID is positive.
+	if err != nil {
+		return errors.Is(err, windows.ERROR_ACCESS_DENIED)
+	}
+	defer windows.CloseHandle(h) //nolint:errcheck // Nothing to do with error.
+	var code uint32
+	err = windows.GetExitCodeProcess(h, &code)
+	return err != nil || code == stillActive
+}
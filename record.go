@@ -0,0 +1,210 @@
+package tail
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"io"
+	"slices"
+)
+
+// minReadSize is the minimum size of buffer used to read from Tail.
+const minReadSize = 4096
+
+// DefaultMaxRecordSize is the default maximum size of a record read by
+// [RecordReader] (including separators, e.g. trailing newline).
+const DefaultMaxRecordSize = 1024 * 1024
+
+// Errors returned by [ScanUvarint] and [RecordReader].
+var (
+	ErrIncompleteRecord = errors.New("incomplete record")
+	ErrRecordTooLong    = errors.New("record too long")
+)
+
+// RecordReader reads records from Tail using [bufio.SplitFunc].
+//
+// It never joins data from different files (e.g. when rotated file ends
+// with incomplete record) or data before and after file truncation:
+// such data is passed to split function with atEOF set to true.
+type RecordReader struct {
+	t     *Tail
+	split bufio.SplitFunc
+	max   int
+	skip  bool // Drop data up to the end of too long record.
+	buf   []byte
+	off   int // Offset of not returned data in buf.
+	gen   int // Tail's gen of data in buf.
+	prev  int // Amount of data in buf which belongs to previous gen.
+	eof   bool
+}
+
+// NewRecordReader returns RecordReader which reads from t records split by
+// split function. It may be one of [bufio.ScanLines], [ScanLF],
+// [ScanCRLF], [ScanNUL], [ScanUvarint] or any other split function.
+//
+// Data must not be read from t directly while it is used by RecordReader.
+func NewRecordReader(t *Tail, split bufio.SplitFunc) *RecordReader {
+	return &RecordReader{
+		t:     t,
+		split: split,
+		max:   DefaultMaxRecordSize,
+		skip:  false,
+		buf:   make([]byte, 0, minReadSize),
+		off:   0,
+		gen:   t.gen,
+		prev:  0,
+		eof:   false,
+	}
+}
+
+// SetMaxRecordSize sets the maximum size of a record (including
+// separators), [DefaultMaxRecordSize] is used by default.
+// Non-positive size is ignored.
+//
+// SetMaxRecordSize must not be called after ReadRecord.
+func (r *RecordReader) SetMaxRecordSize(size int) {
+	if size > 0 {
+		r.max = size
+	}
+}
+
+// ReadRecord returns next record.
+//
+// If Tail returns an error (e.g. because file is inaccessible for
+// [PollTimeout]) then ReadRecord returns this error and keep partial
+// record buffered, following ReadRecord will continue reading same record.
+//
+// Split function is called with atEOF set to true when Tail continues
+// reading from another file (or from the beginning of truncated file) or
+// after Tail returns [io.EOF]. If split function does not return a record
+// in this case then the rest of data is returned as a record.
+// When all data was returned after Tail returns [io.EOF] ReadRecord
+// returns [io.EOF].
+//
+// If split function returns an error then ReadRecord returns this error
+// and drops data passed to split function. If split function needs more
+// data than maximum record size then ReadRecord returns
+// [ErrRecordTooLong] once and drops data up to the end of this record
+// (as found by split function).
+//
+// Returned record is valid only until the next call to ReadRecord.
+//
+// ReadRecord must not be called from simultaneous goroutines.
+func (r *RecordReader) ReadRecord() ([]byte, error) {
+	for {
+		end, atEOF := len(r.buf), r.eof
+		if r.off < r.prev {
+			end, atEOF = r.prev, true
+		}
+		if r.off < end || !atEOF {
+			advance, token, err := r.split(r.buf[r.off:end], atEOF)
+			switch {
+			case err != nil && !errors.Is(err, bufio.ErrFinalToken):
+				r.off, r.skip = end, false
+				return nil, err
+			case atEOF && advance == 0 && token == nil:
+				advance, token = end-r.off, r.buf[r.off:end]
+			}
+			r.off += advance
+			if token != nil && r.skip {
+				r.skip = false
+				continue
+			}
+			if token != nil {
+				return token, nil
+			}
+			if advance > 0 {
+				continue
+			}
+		}
+
+		if r.eof {
+			return nil, io.EOF
+		}
+		if len(r.buf)-r.off >= r.max {
+			r.off = len(r.buf)
+			if !r.skip {
+				r.skip = true
+				return nil, ErrRecordTooLong
+			}
+		}
+		err := r.fill()
+		switch {
+		case errors.Is(err, io.EOF):
+			r.eof = true
+		case err != nil:
+			return nil, err
+		}
+	}
+}
+
+// fill reads more data into buffer.
+func (r *RecordReader) fill() error {
+	r.prev = 0
+	if r.off > 0 {
+		n := copy(r.buf, r.buf[r.off:])
+		r.buf = r.buf[:n]
+		r.off = 0
+	}
+	if len(r.buf) == cap(r.buf) {
+		r.buf = slices.Grow(r.buf, min(cap(r.buf), r.max-len(r.buf)))
+	}
+	n, err := r.t.Read(r.buf[len(r.buf):min(cap(r.buf), r.max)])
+	if n > 0 && r.gen != r.t.gen {
+		r.gen = r.t.gen
+		r.prev = len(r.buf)
+	}
+	r.buf = r.buf[:len(r.buf)+n]
+	return err
+}
+
+// ScanLF is a split function for a [RecordReader] that returns each line
+// of text without trailing "\n". The last line of input will be returned
+// even if it has no newline.
+func ScanLF(data []byte, atEOF bool) (advance int, token []byte, err error) {
+	return scanDelim(data, atEOF, []byte("\n"))
+}
+
+// ScanCRLF is a split function for a [RecordReader] that returns each line
+// of text terminated by "\r\n" without it. The last line of input will be
+// returned even if it has no "\r\n".
+func ScanCRLF(data []byte, atEOF bool) (advance int, token []byte, err error) {
+	return scanDelim(data, atEOF, []byte("\r\n"))
+}
+
+// ScanNUL is a split function for a [RecordReader] that returns each
+// record terminated by NUL byte without it. The last record of input will
+// be returned even if it has no NUL byte.
+func ScanNUL(data []byte, atEOF bool) (advance int, token []byte, err error) {
+	return scanDelim(data, atEOF, []byte{0})
+}
+
+// ScanUvarint is a split function for a [RecordReader] that returns each
+// record prefixed by its length encoded as uvarint (see
+// [binary.AppendUvarint]) without this prefix. It returns
+// [ErrIncompleteRecord] if input ends in the middle of a record and
+// [ErrRecordTooLong] if length does not fit into uint64.
+func ScanUvarint(data []byte, atEOF bool) (advance int, token []byte, err error) {
+	size, n := binary.Uvarint(data)
+	switch {
+	case n < 0:
+		return 0, nil, ErrRecordTooLong
+	case n > 0 && uint64(len(data)-n) >= size:
+		return n + int(size), data[n : n+int(size)], nil //nolint:gosec // Checked above.
+	case atEOF && len(data) > 0:
+		return 0, nil, ErrIncompleteRecord
+	default:
+		return 0, nil, nil
+	}
+}
+
+func scanDelim(data []byte, atEOF bool, delim []byte) (advance int, token []byte, err error) {
+	if i := bytes.Index(data, delim); i >= 0 {
+		return i + len(delim), data[:i], nil
+	}
+	if atEOF && len(data) > 0 {
+		return len(data), data, nil
+	}
+	return 0, nil, nil
+}
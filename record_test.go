@@ -0,0 +1,116 @@
+package tail //nolint:testpackage // TODO
+
+import (
+	"bufio"
+	"encoding/binary"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/powerman/check"
+)
+
+func uvarintRecord(s string) string {
+	return string(binary.AppendUvarint(nil, uint64(len(s)))) + s
+}
+
+func TestRecordReader(tt *testing.T) {
+	tt.Parallel()
+	t := check.Must(tt)
+
+	tail := newTestTail(t)
+	tail.Start()
+	r := NewRecordReader(tail.Tail, ScanUvarint)
+	long := strings.Repeat("x", minReadSize*2)
+	tail.Write(uvarintRecord("new1") + uvarintRecord("") + uvarintRecord(long)[:10])
+	tail.WantRecord(r, "new1", nil)
+	tail.WantRecord(r, "", nil)
+	tail.Write(uvarintRecord(long)[10:] + uvarintRecord("new2")[:3])
+	tail.WantRecord(r, long, nil)
+	tail.Rename()
+	tail.Create()
+	tail.Write(uvarintRecord("new3") + uvarintRecord("new4")[:3])
+	tail.WantRecord(r, "", ErrIncompleteRecord)
+	tail.WantRecord(r, "new3", nil)
+	go func() {
+		time.Sleep(pollDelay * 2)
+		tail.Cancel()
+	}()
+	tail.WantRecord(r, "", ErrIncompleteRecord)
+	tail.WantRecord(r, "", io.EOF)
+
+	tail = newTestTail(t)
+	tail.Start()
+	r = NewRecordReader(tail.Tail, ScanCRLF)
+	tail.Write("new1\r\nnew2\nnew3\r\nnew4\r")
+	tail.WantRecord(r, "new1", nil)
+	tail.WantRecord(r, "new2\nnew3", nil)
+	tail.Rename()
+	tail.Create()
+	tail.Write("\nnew5\r\n")
+	tail.WantRecord(r, "new4\r", nil)
+	tail.WantRecord(r, "\nnew5", nil)
+}
+
+func TestRecordTooLong(tt *testing.T) {
+	tt.Parallel()
+	t := check.Must(tt)
+
+	tail := newTestTail(t)
+	tail.Start()
+	r := NewRecordReader(tail.Tail, ScanLF)
+	r.SetMaxRecordSize(minReadSize * 2)
+	tail.Write("new1\n" + strings.Repeat("x", minReadSize*3) + "\nnew2\n")
+	tail.WantRecord(r, "new1", nil)
+	tail.WantRecord(r, "", ErrRecordTooLong)
+	tail.WantRecord(r, "new2", nil) // Tail of too long record is dropped.
+
+	tail = newTestTail(t)
+	tail.Start()
+	r = NewRecordReader(tail.Tail, ScanUvarint)
+	r.SetMaxRecordSize(minReadSize)
+	tail.Write(string(binary.AppendUvarint(nil, 1<<40)) + strings.Repeat("x", minReadSize))
+	tail.WantRecord(r, "", ErrRecordTooLong)
+}
+
+func TestScan(tt *testing.T) {
+	tt.Parallel()
+	t := check.Must(tt)
+
+	tests := []struct {
+		split   bufio.SplitFunc
+		data    string
+		atEOF   bool
+		advance int
+		token   string
+		err     error
+	}{
+		{ScanLF, "", false, 0, "", nil},
+		{ScanLF, "", true, 0, "", nil},
+		{ScanLF, "a\r\nb", false, 3, "a\r", nil},
+		{ScanLF, "ab", false, 0, "", nil},
+		{ScanLF, "ab", true, 2, "ab", nil},
+		{ScanCRLF, "a\nb\r\nc", false, 5, "a\nb", nil},
+		{ScanCRLF, "a\r", false, 0, "", nil},
+		{ScanCRLF, "a\r", true, 2, "a\r", nil},
+		{ScanNUL, "a\nb\x00c", false, 4, "a\nb", nil},
+		{ScanNUL, "\x00", false, 1, "", nil},
+		{ScanNUL, "a", true, 1, "a", nil},
+		{ScanUvarint, "", false, 0, "", nil},
+		{ScanUvarint, "", true, 0, "", nil},
+		{ScanUvarint, "\x00", false, 1, "", nil},
+		{ScanUvarint, "\x02ab\x01", false, 3, "ab", nil},
+		{ScanUvarint, "\x02a", false, 0, "", nil},
+		{ScanUvarint, "\x02a", true, 0, "", ErrIncompleteRecord},
+		{ScanUvarint, "\x80", false, 0, "", nil},
+		{ScanUvarint, "\x80", true, 0, "", ErrIncompleteRecord},
+		{ScanUvarint, strings.Repeat("\xff", 10) + "\x01", false, 0, "", ErrRecordTooLong},
+	}
+	for _, tc := range tests {
+		advance, token, err := tc.split([]byte(tc.data), tc.atEOF)
+		t.Err(err, tc.err, "%q %v", tc.data, tc.atEOF)
+		t.Equal(advance, tc.advance, "%q %v", tc.data, tc.atEOF)
+		t.Equal(string(token), tc.token, "%q %v", tc.data, tc.atEOF)
+	}
+}
@@ -0,0 +1,41 @@
+package tail //nolint:testpackage // TODO
+
+import (
+	"bytes"
+	"fmt"
+	"log/slog"
+	"strings"
+	"testing"
+
+	"github.com/powerman/check"
+)
+
+func TestSlog(tt *testing.T) {
+	tt.Parallel()
+	t := check.Must(tt)
+	tail := newTestTail(t)
+
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewTextHandler(&buf, &slog.HandlerOptions{
+		ReplaceAttr: func(_ []string, a slog.Attr) slog.Attr {
+			if a.Key == slog.TimeKey {
+				return slog.Attr{}
+			}
+			return a
+		},
+	}))
+	tail.Remove()
+	tail.Start(Slog(logger))
+
+	tail.Create()
+	tail.Write("data")
+	fi, err := tail.f.Stat()
+	t.Nil(err)
+
+	tail.WantChunk("data", nil)
+	t.DeepEqual(strings.Split(buf.String(), "\n"), []string{
+		fmt.Sprintf(`level=WARN msg="cannot open for reading" path=%s error="no such file or directory"`, tail.path),
+		fmt.Sprintf(`level=INFO msg="file has appeared" path=%s inode=%d offset=0`, tail.path, getFileID(tail.f, fi).Ino),
+		"",
+	})
+}
@@ -0,0 +1,154 @@
+package tail
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"io"
+	"log/slog"
+	"time"
+)
+
+// linesBlockSize is the size of blocks used to search lines backwards.
+const linesBlockSize = 64 * 1024
+
+// startFunc returns offset in usual file t.f where tailing should begin.
+type startFunc func(t *Tail) (int64, error)
+
+// seekStart set initial offset in the file opened by Follow: saved
+// position, beginning of rotated files (with [CatchUp]) or start offset.
+func (t *Tail) seekStart() error {
+	if t.resume != nil && t.f.Match(*t.resume) {
+		_, err := t.f.Seek(t.resume.Offset, io.SeekStart)
+		return err
+	}
+	if t.catchUp && t.readRotated() {
+		return nil
+	}
+	if t.resume != nil {
+		t.logf(slog.LevelWarn, t.f, nil, "file does not match saved position",
+			"tail: %q does not match saved position", t.f.path)
+	}
+	offset, err := t.start(t)
+	if err == nil {
+		_, err = t.f.Seek(offset, io.SeekStart)
+	}
+	return err
+}
+
+func startWhence(whence int) startFunc {
+	return func(t *Tail) (int64, error) {
+		return t.f.File.Seek(0, whence)
+	}
+}
+
+func startOffset(offset int64, whence int) startFunc {
+	return func(t *Tail) (int64, error) {
+		size := t.f.info.Size()
+		if whence == io.SeekEnd {
+			offset += size
+		}
+		switch {
+		case offset < 0:
+			return 0, nil
+		case offset > size:
+			t.logf(slog.LevelWarn, t.f, nil, "offset is beyond end of file",
+				"tail: %q: offset %d is beyond end of file (%d bytes)", t.f.path, offset, size)
+			return size, nil
+		default:
+			return offset, nil
+		}
+	}
+}
+
+func startLines(n int) startFunc {
+	return func(t *Tail) (int64, error) {
+		return lastLines(t.f, t.f.info.Size(), n)
+	}
+}
+
+func startSince(since time.Time, extract TimestampFunc) startFunc {
+	return func(t *Tail) (int64, error) {
+		return sinceOffset(t.f, t.f.info.Size(), since, extract)
+	}
+}
+
+// sinceOffset returns offset of the first line in r of given size which
+// has a timestamp not before since. It uses binary search, so lines must
+// be ordered by their timestamps.
+func sinceOffset(r io.ReaderAt, size int64, since time.Time, extract TimestampFunc) (int64, error) {
+	found := size
+	for lo, hi := int64(0), size; lo < hi; {
+		mid := lo + (hi-lo)/2
+		start, end, ts, err := nextStamped(r, size, mid, hi, extract)
+		switch {
+		case err != nil:
+			return 0, err
+		case start >= hi:
+			hi = mid
+		case ts.Before(since):
+			lo = end
+		default:
+			found, hi = start, mid
+		}
+	}
+	return found, nil
+}
+
+// nextStamped returns first line in r of given size with a timestamp which
+// begins at or after offset and before limit. If there is no such line
+// then returned start and end are equal to limit.
+func nextStamped(r io.ReaderAt, size, offset, limit int64, extract TimestampFunc) (start, end int64, ts time.Time, err error) {
+	pos := max(offset-1, 0)
+	br := bufio.NewReader(io.NewSectionReader(r, pos, size-pos))
+	if offset > 0 { // Skip rest of the line which contains byte before offset.
+		var skip []byte
+		skip, err = br.ReadBytes('\n')
+		pos += int64(len(skip))
+	}
+	for err == nil && pos < limit {
+		var line []byte
+		line, err = br.ReadBytes('\n')
+		if len(line) == 0 {
+			break
+		}
+		start, pos = pos, pos+int64(len(line))
+		ts, ok := extract(bytes.TrimSuffix(line, []byte("\n")))
+		if ok {
+			return start, pos, ts, nil
+		}
+	}
+	if errors.Is(err, io.EOF) {
+		err = nil
+	}
+	return limit, limit, time.Time{}, err
+}
+
+// lastLines returns offset of the beginning of last n lines in r of given
+// size. It reads r backwards by blocks, so it's fast even for huge files.
+func lastLines(r io.ReaderAt, size int64, n int) (int64, error) {
+	if n <= 0 {
+		return size, nil
+	}
+	buf := make([]byte, min(size, linesBlockSize))
+	for end := size; end > 0; {
+		start := max(0, end-int64(len(buf)))
+		b := buf[:end-start]
+		_, err := r.ReadAt(b, start)
+		if err != nil {
+			return 0, err
+		}
+		for i := len(b) - 1; i >= 0; i-- {
+			pos := start + int64(i)
+			if b[i] != '\n' || pos == size-1 { // Last line's newline does not start a line.
+				continue
+			}
+			n--
+			if n == 0 {
+				return pos + 1, nil
+			}
+		}
+		end = start
+	}
+	return 0, nil
+}
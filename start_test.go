@@ -0,0 +1,148 @@
+package tail //nolint:testpackage // TODO
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/powerman/check"
+)
+
+func TestLines(tt *testing.T) {
+	tt.Parallel()
+	t := check.Must(tt)
+	tail := newTestTail(t)
+
+	tail.Write("old1\nold2\nold3\nold4\n")
+	tail.Run(Lines(2))
+
+	tail.Want(pollDelay*3/2, "old3\nold4\n", nil)
+
+	tail.Write("new1\n")
+	tail.Want(pollDelay*3/2, "new1\n", nil)
+}
+
+func TestSince(tt *testing.T) {
+	tt.Parallel()
+	t := check.Must(tt)
+	tail := newTestTail(t)
+
+	tail.Write("2026-10-16T10:41:00Z old1\n2026-10-16T10:42:00Z old2\n2026-10-16T10:43:00Z old3\n")
+	tail.Run(Since(time.Date(2026, 10, 16, 10, 42, 0, 0, time.UTC), func(line []byte) (time.Time, bool) {
+		ts, err := time.Parse(time.RFC3339, string(bytes.Fields(line)[0]))
+		return ts, err == nil
+	}))
+
+	tail.Want(pollDelay*3/2, "2026-10-16T10:42:00Z old2\n2026-10-16T10:43:00Z old3\n", nil)
+
+	tail.Write("new1\n")
+	tail.Want(pollDelay*3/2, "new1\n", nil)
+}
+
+func TestOffset(tt *testing.T) {
+	tt.Parallel()
+	t := check.Must(tt)
+
+	tests := []struct {
+		offset int64
+		whence int
+		want   string
+	}{
+		{0, io.SeekStart, "old1\nold2\n"},
+		{3, io.SeekStart, "1\nold2\n"},
+		{10, io.SeekStart, ""},
+		{20, io.SeekStart, ""},
+		{-1, io.SeekStart, "old1\nold2\n"},
+		{0, io.SeekEnd, ""},
+		{-3, io.SeekEnd, "d2\n"},
+		{-20, io.SeekEnd, "old1\nold2\n"},
+		{20, io.SeekEnd, ""},
+	}
+	for _, tc := range tests {
+		tail := newTestTail(t)
+		tail.Write("old1\nold2\n")
+		tail.Run(Offset(tc.offset, tc.whence))
+		tail.Want(pollDelay*3/2, tc.want, nil)
+		tail.Write("new1\n")
+		tail.Want(pollDelay*3/2, "new1\n", nil)
+	}
+}
+
+func TestLastLines(tt *testing.T) {
+	tt.Parallel()
+	t := check.Must(tt)
+
+	huge := strings.Repeat("0123456789abcdef\n", linesBlockSize/4)
+	tests := []struct {
+		s    string
+		n    int
+		want string
+	}{
+		{"", 0, ""},
+		{"", 1, ""},
+		{"a\nb\nc\n", 0, ""},
+		{"a\nb\nc\n", 1, "c\n"},
+		{"a\nb\nc\n", 2, "b\nc\n"},
+		{"a\nb\nc\n", 3, "a\nb\nc\n"},
+		{"a\nb\nc\n", 4, "a\nb\nc\n"},
+		{"a\nb\nc", 1, "c"},
+		{"a\nb\nc", 2, "b\nc"},
+		{"\n\n\n", 2, "\n\n"},
+		{"\n\n\n", 5, "\n\n\n"},
+		{huge + "x\n", 2, "0123456789abcdef\nx\n"},
+		{huge + "x", 3, "0123456789abcdef\n0123456789abcdef\nx"},
+		{huge, linesBlockSize / 4, huge},
+		{huge, linesBlockSize/4 - 1, huge[17:]},
+	}
+	for _, tc := range tests {
+		offset, err := lastLines(strings.NewReader(tc.s), int64(len(tc.s)), tc.n)
+		t.Nil(err)
+		t.Equal(tc.s[offset:], tc.want, "%q %d", tc.s[max(0, len(tc.s)-10):], tc.n)
+	}
+}
+
+func TestSinceOffset(tt *testing.T) {
+	tt.Parallel()
+	t := check.Must(tt)
+
+	extract := func(line []byte) (time.Time, bool) {
+		ts, err := time.Parse(time.TimeOnly, string(line[:min(len(line), len(time.TimeOnly))]))
+		return ts, err == nil
+	}
+	at := func(s string) time.Time {
+		ts, err := time.Parse(time.TimeOnly, s)
+		t.Nil(err)
+		return ts
+	}
+
+	log := "10:00:00 a\n10:00:01 b\n\tcont\n10:00:02 c\n10:00:02 d\n\tcont\n\tcont\n10:00:05 e\n"
+	var huge strings.Builder
+	for i := range 10000 {
+		fmt.Fprintf(&huge, "%s line %d\n", time.Time{}.Add(time.Duration(i)*time.Second).Format(time.TimeOnly), i)
+	}
+	tests := []struct {
+		s     string
+		since string
+		want  string
+	}{
+		{"", "10:00:00", ""},
+		{"garbage\n", "10:00:00", ""},
+		{log, "09:00:00", log},
+		{log, "10:00:00", log},
+		{log, "10:00:01", log[11:]},
+		{log, "10:00:02", "10:00:02 c\n10:00:02 d\n\tcont\n\tcont\n10:00:05 e\n"},
+		{log, "10:00:03", "10:00:05 e\n"},
+		{log, "10:00:05", "10:00:05 e\n"},
+		{log, "10:00:06", ""},
+		{"\tcont\n10:00:01 a\n10:00:02 b", "10:00:02", "10:00:02 b"},
+		{huge.String(), "02:00:00", "02:00:00 line 7200\n" + strings.SplitN(huge.String(), "line 7200\n", 2)[1]},
+	}
+	for _, tc := range tests {
+		offset, err := sinceOffset(strings.NewReader(tc.s), int64(len(tc.s)), at(tc.since), extract)
+		t.Nil(err)
+		t.Equal(tc.s[offset:], tc.want, "%q %s", tc.s[:min(len(tc.s), 20)], tc.since)
+	}
+}
@@ -0,0 +1,140 @@
+package tail
+
+import (
+	"os"
+	"slices"
+	"sync/atomic"
+	"time"
+)
+
+// Stats is a snapshot of Tail metrics.
+type Stats struct {
+	BytesRead    int64         // Amount of data returned by Read.
+	Reads        int64         // Amount of Read calls which returned data.
+	Rotations    int64         // Amount of switches to new or truncated file.
+	OpenFailures int64         // Amount of times path was found inaccessible by Follow or after rotation.
+	ReadErrors   int64         // Amount of read errors.
+	LastData     time.Time     // When data was read last time (or Follow was called).
+	Idle         time.Duration // Time since LastData.
+	Lag          int64         // Amount of data in current and next usual files not read yet.
+}
+
+// MetricsSink receives Tail metrics updates, it may be used to export
+// metrics to Prometheus, expvar, etc. Use [Tail.Stats] to get gauges.
+//
+// Methods are called synchronously by Follow and Read, so they should
+// not block.
+type MetricsSink interface {
+	// Read is called when Read returns n bytes of data.
+	Read(path string, n int)
+	// Rotated is called when Tail switches to new or truncated file.
+	Rotated(path string)
+	// OpenFailed is called when path was found inaccessible by Follow
+	// or after rotation (but not on retries to open it).
+	OpenFailed(path string)
+	// ReadFailed is called on read error.
+	ReadFailed(path string)
+}
+
+type tailStats struct {
+	bytesRead    atomic.Int64
+	reads        atomic.Int64
+	rotations    atomic.Int64
+	openFailures atomic.Int64
+	readErrors   atomic.Int64
+	lastData     atomic.Int64 // Unix time in nanoseconds.
+	file         atomic.Pointer[os.File]
+	offset       atomic.Int64
+	pending      atomic.Pointer[[]*os.File] // Opened files to read after file.
+	pendingOf    pendingKey                 // Used to update pending only on changes.
+}
+
+// pendingKey identifies state of files to read after current one.
+type pendingKey struct {
+	next   *trackedFile
+	opened bool
+	queue  int
+}
+
+// Stats returns current Tail metrics.
+//
+// Unlike other methods it is safe to call Stats from any goroutine.
+func (t *Tail) Stats() Stats {
+	lastData := time.Unix(0, t.stats.lastData.Load())
+	s := Stats{
+		BytesRead:    t.stats.bytesRead.Load(),
+		Reads:        t.stats.reads.Load(),
+		Rotations:    t.stats.rotations.Load(),
+		OpenFailures: t.stats.openFailures.Load(),
+		ReadErrors:   t.stats.readErrors.Load(),
+		LastData:     lastData,
+		Idle:         time.Since(lastData),
+		Lag:          0,
+	}
+	if file := t.stats.file.Load(); file != nil {
+		fi, err := file.Stat()
+		if err == nil && fi.Mode().IsRegular() {
+			s.Lag = max(0, fi.Size()-t.stats.offset.Load())
+		}
+	}
+	if pending := t.stats.pending.Load(); pending != nil {
+		for _, file := range *pending {
+			fi, err := file.Stat()
+			if err == nil && fi.Mode().IsRegular() {
+				s.Lag += fi.Size()
+			}
+		}
+	}
+	return s
+}
+
+// track makes current file, offset and opened next files available to Stats.
+func (t *Tail) track() {
+	if t.f.z == nil {
+		t.stats.file.Store(t.f.File)
+	} else {
+		t.stats.file.Store(nil) // Size of compressed file can't be used to calculate lag.
+	}
+	t.stats.offset.Store(t.f.offset)
+
+	key := pendingKey{next: t.next, opened: t.next != nil && t.next.Opened(), queue: len(t.queue)}
+	if key != t.stats.pendingOf {
+		t.stats.pendingOf = key
+		var pending []*os.File
+		for _, f := range slices.Concat(t.queue, []*trackedFile{t.next}) {
+			if f != nil && f.Opened() && f.z == nil {
+				pending = append(pending, f.File)
+			}
+		}
+		t.stats.pending.Store(&pending)
+	}
+}
+
+func (t *Tail) countRead(n int) {
+	t.stats.bytesRead.Add(int64(n))
+	t.stats.reads.Add(1)
+	t.stats.lastData.Store(time.Now().UnixNano())
+	if t.sink != nil {
+		t.sink.Read(t.path, n)
+	}
+}
+
+func (t *Tail) countEvent(ev Event) {
+	switch ev.Type { //nolint:exhaustive // Other events are not counted.
+	case EventSwitched, EventTruncated:
+		t.stats.rotations.Add(1)
+		if t.sink != nil {
+			t.sink.Rotated(t.path)
+		}
+	case EventDisappeared:
+		t.stats.openFailures.Add(1)
+		if t.sink != nil {
+			t.sink.OpenFailed(t.path)
+		}
+	case EventReadError:
+		t.stats.readErrors.Add(1)
+		if t.sink != nil {
+			t.sink.ReadFailed(t.path)
+		}
+	}
+}
@@ -0,0 +1,51 @@
+package tail //nolint:testpackage // TODO
+
+import (
+	"testing"
+
+	"github.com/powerman/check"
+)
+
+type testSink struct {
+	read, rotated, openFailed, readFailed int
+}
+
+func (s *testSink) Read(_ string, n int) { s.read += n }
+func (s *testSink) Rotated(string)       { s.rotated++ }
+func (s *testSink) OpenFailed(string)    { s.openFailed++ }
+func (s *testSink) ReadFailed(string)    { s.readFailed++ }
+
+func TestStats(tt *testing.T) {
+	tt.Parallel()
+	t := check.Must(tt)
+	tail := newTestTail(t)
+
+	sink := &testSink{}
+	tail.Remove()
+	tail.Start(Metrics(sink))
+	s := tail.Stats()
+	t.Equal(s.OpenFailures, int64(1))
+	t.Equal(s.Lag, int64(0))
+
+	tail.Create()
+	tail.Write("data")
+	tail.WantChunk("dat", nil)
+	s = tail.Stats()
+	t.Equal(s.BytesRead, int64(3))
+	t.Equal(s.Reads, int64(1))
+	t.Equal(s.Lag, int64(1))
+	t.Less(s.Idle, pollTimeout)
+
+	tail.Rename()
+	tail.Create()
+	tail.Write("new")
+	tail.WantChunk("a", nil)
+	t.Equal(tail.Stats().Lag, int64(3)) // Includes opened new file.
+	tail.WantChunk("new", nil)
+	s = tail.Stats()
+	t.Equal(s.BytesRead, int64(7))
+	t.Equal(s.Reads, int64(3))
+	t.Equal(s.Rotations, int64(1))
+	t.Equal(s.Lag, int64(0))
+	t.Equal(*sink, testSink{read: 7, rotated: 1, openFailed: 1, readFailed: 0})
+}
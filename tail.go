@@ -1,15 +1,21 @@
-// Package tail implements behaviour of `tail -n 0 -F path`.
+// Package tail implements behaviour of `tail -F path` (or `tail -f path`)
+// to follow rotated log files.
 package tail
 
 import (
 	"context"
 	"errors"
 	"io"
+	"log/slog"
 	"os"
+	"sync/atomic"
 	"time"
 )
 
-// Tail is an [io.Reader] with `tail -n 0 -F path` behaviour.
+// Tail is an [io.Reader] with `tail -n 0 -F path` behaviour by default.
+// Use options to start from another position (e.g. [Lines] for
+// `tail -n N -F path`), to follow the opened file like `tail -f path`
+// ([FollowDescriptor]) or to use inotify instead of polling ([Inotify]).
 //
 // Unlike `tail` it does track renamed/removed file contents up to the
 // moment new file will be created with original name - this ensure no
@@ -17,52 +23,126 @@ import (
 // the one which write to log file) and thus original log file may be
 // appended between rename/removal and reopening.
 //
-// Unlike `tail` it does not support file truncation. While this can't
-// work reliable, truncate support may be added in the future.
+// File truncation is detected only when the file becomes smaller than
+// current offset, so it can't work reliable: if file is truncated and then
+// grows above current offset between two reads truncation won't be noticed.
+// By default Tail continues reading from the beginning of truncated file,
+// see [Truncation] to change this.
 type Tail struct {
 	ctx         context.Context //nolint:containedctx // By design.
 	log         Logger
+	slog        *slog.Logger
 	path        string
 	pollDelay   time.Duration
 	pollTimeout time.Duration
+	newWatcher  NewWatcher
+	watcher     Watcher
+	active      time.Time // When data was read, file was opened or detached last time.
 	f           *trackedFile
 	next        *trackedFile
 	lasterr     error
-	whence      int
+	gen         int // Incremented when Read continues with another data stream.
+	start       startFunc
+	resume      *Position
+	truncate    TruncateMode
+	events      func(Event)
+	stats       tailStats
+	sink        MetricsSink
+	stopAtEOF   atomic.Bool // Used by Glob to retire Tail for vanished file.
+	latest      LatestBy
+	seen        map[string]bool // Paths of files already followed in Latest mode.
+	resolved    string          // Cached result of resolve in Latest mode.
+	resolvedAt  time.Time
+	catchUp     bool
+	queue       []*trackedFile // Files to read after t.f (before t.next).
+	descriptor  bool
+	pid         int
+	pidExited   bool
+	drain       time.Duration
+	draining    bool
+	fctx        context.Context    //nolint:containedctx // Used by opened files and watcher, may outlive ctx to drain data.
+	cancel      context.CancelFunc // Cancels fctx.
 }
 
-// Follow starts tracking the path using polling.
+// Follow starts tracking the path using polling (see [Watch] to change
+// this). By default it follows the path, see [FollowDescriptor] to follow
+// the opened file instead.
 //
-// If path already exists tracking begins from the end of the file.
+// If path already exists tracking begins from the end of the file
+// (see [Whence], [Offset], [Lines], [Since], [Resume] and [CatchUp] to
+// change this).
 //
 // Supported path types: usual file, FIFO and symlink to usual or FIFO.
 func Follow(ctx context.Context, log Logger, path string, options ...Option) *Tail {
+	t := newTail(ctx, log, path, options...)
+	t.fctx, t.cancel = context.WithCancel(drainContext(ctx, t.drain))
+	t.initWatcher()
+	t.f = newTrackedFile(t.fctx, t.resolve())
+
+	err := t.f.Open() //nolint:contextcheck // False positive.
+	if err == nil && t.f.Usual() {
+		err = t.seekStart()
+		if err != nil {
+			t.f.Close()
+		}
+	}
+	if err != nil {
+		t.logf(slog.LevelWarn, t.f, unwrap(err), "cannot open for reading",
+			"tail: cannot open %q for reading: %s", t.f.path, unwrap(err))
+		t.emit(Event{Type: EventDisappeared, Path: t.f.path, Err: unwrap(err)})
+	} else {
+		t.emit(Event{Type: EventOpened, Path: t.f.path, New: t.f.id, NewOffset: t.f.offset})
+	}
+	if t.catchUp && !t.f.Opened() && !t.readRotated() {
+		t.logf(slog.LevelWarn, t.f, nil, "file does not match saved position",
+			"tail: %q does not match saved position", t.f.path)
+	}
+	t.track()
+
+	return t
+}
+
+// newTail returns Tail with applied options. It does not open path.
+func newTail(ctx context.Context, log Logger, path string, options ...Option) *Tail {
 	t := &Tail{
 		ctx:         ctx,
 		log:         log,
+		slog:        nil,
 		path:        path,
 		pollDelay:   DefaultPollDelay,
 		pollTimeout: DefaultPollTimeout,
-		f:           newTrackedFile(ctx, path),
+		newWatcher:  nil,
+		watcher:     nil,
+		active:      time.Now(),
+		f:           nil,
 		next:        nil,
 		lasterr:     nil,
-		whence:      io.SeekEnd,
+		gen:         0,
+		start:       startWhence(io.SeekEnd),
+		resume:      nil,
+		truncate:    TruncateRestart,
+		events:      nil,
+		stats:       tailStats{},
+		sink:        nil,
+		stopAtEOF:   atomic.Bool{},
+		latest:      0,
+		seen:        make(map[string]bool),
+		resolved:    "",
+		resolvedAt:  time.Time{},
+		catchUp:     false,
+		queue:       nil,
+		descriptor:  false,
+		pid:         0,
+		pidExited:   false,
+		drain:       0,
+		draining:    false,
+		fctx:        ctx,
+		cancel:      nil,
 	}
+	t.stats.lastData.Store(time.Now().UnixNano())
 	for _, option := range options {
 		option.apply(t)
 	}
-
-	err := t.f.Open() //nolint:contextcheck // False positive.
-	if err == nil && t.f.Usual() {
-		_, err = t.f.Seek(0, t.whence)
-		if err != nil {
-			t.f.Close()
-		}
-	}
-	if err != nil {
-		t.log.Printf("tail: cannot open %q for reading: %s", t.path, unwrap(err))
-	}
-
 	return t
 }
 
@@ -70,21 +150,35 @@ func Follow(ctx context.Context, log Logger, path string, options ...Option) *Ta
 // file if it is inaccessible, and continue reading from beginning of the
 // file when it will became accessible (e.g. after log rotation).
 //
-// Returned data is not guaranteed to contain full lines of text.
+// Returned data is not guaranteed to contain full lines of text, use
+// [LineReader] to read full lines.
 //
 // If Read returns any error except [io.EOF], then following Read will
 // return either some data or [io.EOF].
 //
 // Read may return 0, nil only if len(p) == 0.
 //
-// Read will return [io.EOF] only after cancelling ctx.
-// Following Read will always return [io.EOF].
+// If file was truncated and [TruncateError] mode is used then Read returns
+// [ErrTruncated] and following Read will continue from the beginning of
+// the file.
+//
+// Read will return [io.EOF] only after cancelling ctx (or, when [PID]
+// is used, after the process has exited and all data was read).
+// See [Drain] to read data already written to the file after cancelling
+// ctx.
+// Files opened by Tail are closed after Read returns [io.EOF] and
+// following Read will always return [io.EOF].
 //
 // Read must not be called from simultaneous goroutines.
 func (t *Tail) Read(p []byte) (int, error) {
 	if errors.Is(t.lasterr, io.EOF) {
 		return 0, t.lasterr
 	}
+	defer func() {
+		if errors.Is(t.lasterr, io.EOF) {
+			t.close()
+		}
+	}()
 
 	if len(p) == 0 {
 		return 0, nil
@@ -108,72 +202,222 @@ func (t *Tail) Read(p []byte) (int, error) {
 	for n == 0 && t.lasterr == nil {
 		n, t.lasterr = t.read(timeoutc, p)
 	}
+	t.track()
+	if n > 0 {
+		t.countRead(n)
+	}
 	return n, t.lasterr
 }
 
 func (t *Tail) tryOpen(timeoutc <-chan time.Time) error {
 	for err := t.f.Open(); err != nil; err = t.f.Open() {
+		if t.stopAtEOF.Load() || t.pid != 0 && !processExists(t.pid) {
+			return io.EOF
+		}
 		select {
-		case <-time.After(t.pollDelay):
+		case <-t.changed(true):
 		case <-timeoutc:
 			return unwrap(err)
 		case <-t.ctx.Done():
 			return io.EOF
 		}
+		t.f.path = t.resolve()
 	}
-	t.log.Printf("tail: %q has appeared;  following new file", t.path)
+	t.active = time.Now()
+	t.logf(slog.LevelInfo, t.f, nil, "file has appeared",
+		"tail: %q has appeared;  following new file", t.f.path)
+	t.emit(Event{Type: EventOpened, Path: t.f.path, New: t.f.id})
 	return nil
 }
 
 func (t *Tail) openNext() (err error) {
-	if t.next == nil && t.f.Detached() { //nolint:nestif // TODO
-		t.next = newTrackedFile(t.ctx, t.path)
+	if t.next == nil && !t.descriptor && t.detached() { //nolint:nestif // TODO
+		t.active = time.Now()
+		t.emit(Event{Type: EventDetached, Path: t.f.path, Old: t.f.id, OldOffset: t.f.offset})
+		t.next = newTrackedFile(t.fctx, t.resolve())
 		err = unwrap(t.next.Open())
 		if err != nil {
-			t.log.Printf("tail: %q has become inaccessible: %s", t.path, err)
+			t.logf(slog.LevelWarn, t.next, err, "file has become inaccessible",
+				"tail: %q has become inaccessible: %s", t.next.path, err)
+			t.emit(Event{Type: EventDisappeared, Path: t.next.path, Old: t.f.id, OldOffset: t.f.offset, Err: err})
 		} else {
-			t.log.Printf("tail: %q has been replaced;  following new file", t.path)
+			t.logf(slog.LevelInfo, t.next, nil, "file has been replaced",
+				"tail: %q has been replaced;  following new file", t.next.path)
+			t.emit(Event{Type: EventReplaced, Path: t.next.path, Old: t.f.id, OldOffset: t.f.offset, New: t.next.id})
 		}
 	} else if t.next != nil && !t.next.Opened() {
+		t.next.path = t.resolve()
 		err = unwrap(t.next.Open())
 		if err == nil {
-			t.log.Printf("tail: %q has appeared;  following new file", t.path)
+			t.active = time.Now()
+			t.logf(slog.LevelInfo, t.next, nil, "file has appeared",
+				"tail: %q has appeared;  following new file", t.next.path)
+			t.emit(Event{Type: EventOpened, Path: t.next.path, Old: t.f.id, OldOffset: t.f.offset, New: t.next.id})
 		}
 	}
 	return err
 }
 
 func (t *Tail) read(timeoutc <-chan time.Time, p []byte) (int, error) {
-	errOpen := t.openNext()
+	errOpen := t.prepareRead()
 
 	n, err := t.f.Read(p)
 	err = unwrap(err)
+	if errors.Is(err, io.EOF) && t.f.Truncated() {
+		err = t.restart()
+		if err == nil {
+			return t.read(timeoutc, p)
+		}
+		if errors.Is(err, ErrTruncated) {
+			return 0, err
+		}
+	}
 	if errors.Is(err, io.EOF) && t.next != nil && t.next.Opened() {
-		t.f.Close()
-		t.f, t.next = t.next, nil
+		t.switchNext()
 		return t.read(timeoutc, p)
 	}
 
 	switch {
 	case err == nil:
+		t.active = time.Now()
 		return n, nil
 	case errors.Is(err, os.ErrClosed):
 		return 0, io.EOF
+	case errors.Is(err, io.EOF) && t.stopped():
+		return 0, io.EOF
+	case errors.Is(err, io.EOF) && t.processExited():
+		return t.read(timeoutc, p) // Read data written before process has exited.
 	case errors.Is(err, io.EOF):
 		err = errOpen
 	default:
-		t.log.Printf("tail: error reading %q: %s", t.path, err)
+		t.logf(slog.LevelError, t.f, err, "error reading",
+			"tail: error reading %q: %s", t.f.path, err)
+		t.emit(Event{Type: EventReadError, Path: t.f.path, Old: t.f.id, OldOffset: t.f.offset, Err: err})
+		if t.draining {
+			return 0, io.EOF
+		}
+	}
+	return 0, t.wait(timeoutc, err)
+}
+
+// prepareRead starts draining after ctx is done, takes next file from
+// CatchUp queue and (unless draining) tries to open next file.
+func (t *Tail) prepareRead() (errOpen error) {
+	if t.drain > 0 && !t.draining && t.ctx.Err() != nil {
+		t.draining = true
+	}
+	if t.next == nil && len(t.queue) > 0 {
+		t.next, t.queue = t.queue[0], t.queue[1:]
+	}
+	if t.draining {
+		return nil
+	}
+	return t.openNext()
+}
+
+// restart continues reading truncated file from the beginning.
+// It returns ErrTruncated in TruncateError mode.
+func (t *Tail) restart() error {
+	t.logf(slog.LevelInfo, t.f, nil, "file truncated",
+		"tail: %q: file truncated", t.f.path)
+	t.emit(Event{Type: EventTruncated, Path: t.f.path, Old: t.f.id, OldOffset: t.f.offset, New: t.f.id})
+	_, err := t.f.Seek(0, io.SeekStart)
+	if err != nil {
+		return unwrap(err)
+	}
+	t.f.fp, t.f.fpDone = 0, false // File content may be replaced.
+	t.gen++
+	if t.truncate == TruncateError {
+		return ErrTruncated
+	}
+	return nil
+}
+
+// switchNext continues reading next file after current one was read.
+func (t *Tail) switchNext() {
+	t.emit(Event{Type: EventSwitched, Path: t.next.path, Old: t.f.id, OldOffset: t.f.offset, New: t.next.id})
+	if t.latest != 0 {
+		t.seen[t.f.path] = true
+		t.resolvedAt = time.Time{} // Resolved path may be seen now.
+	}
+	t.f.Close()
+	t.f, t.next = t.next, nil
+	t.gen++
+}
+
+// close releases opened files and watcher. Following Read will return
+// [io.EOF].
+func (t *Tail) close() {
+	t.cancel()
+}
+
+// stopped reports whether Read should return [io.EOF] at the end of file.
+func (t *Tail) stopped() bool {
+	return t.stopAtEOF.Load() || t.pidExited || t.draining
+}
+
+// processExited reports whether process given to [PID] has just exited.
+func (t *Tail) processExited() bool {
+	if t.pid == 0 || t.pidExited || processExists(t.pid) {
+		return false
 	}
+	t.pidExited = true
+	return true
+}
 
+// wait waits until watcher reports a change (returns nil), timeoutc fires
+// (returns err) or ctx is done (returns [io.EOF] or nil to start draining).
+func (t *Tail) wait(timeoutc <-chan time.Time, err error) error {
 	if err == nil {
 		timeoutc = nil
 	}
+	missing := t.next != nil && !t.next.Opened()
 	select {
-	case <-time.After(t.pollDelay):
-		return 0, nil
+	case <-t.changed(missing):
+		return nil
 	case <-timeoutc:
-		return 0, err
+		return err
 	case <-t.ctx.Done():
-		return 0, io.EOF
+		if t.drain > 0 && !t.draining {
+			return nil
+		}
+		return io.EOF
+	}
+}
+
+func (t *Tail) initWatcher() {
+	if t.newWatcher == nil {
+		t.newWatcher = PollWatcher(t.pollDelay)
+	}
+	var err error
+	t.watcher, err = t.newWatcher(t.fctx, t.path)
+	if err != nil {
+		t.logf(slog.LevelWarn, nil, err, "cannot watch, reverting to polling",
+			"tail: cannot watch %q, reverting to polling: %s", t.path, err)
+		t.watcher, _ = PollWatcher(t.pollDelay)(t.fctx, t.path)
+	}
+}
+
+// changed returns watcher's channel, it replaces watcher with polling if
+// watcher has stopped working.
+func (t *Tail) changed(missing bool) <-chan struct{} {
+	if w, ok := t.watcher.(failingWatcher); ok {
+		if err := w.Err(); err != nil {
+			t.logf(slog.LevelWarn, nil, err, "cannot watch, reverting to polling",
+				"tail: cannot watch %q, reverting to polling: %s", t.path, err)
+			t.watcher, _ = PollWatcher(t.pollDelay)(t.fctx, t.path)
+		}
+	}
+	return t.watcher.Changed(missing, time.Since(t.active))
+}
+
+// drainContext returns context which is done after drain timeout since
+// ctx is done (or ctx itself if drain is not positive).
+func drainContext(ctx context.Context, drain time.Duration) context.Context {
+	if drain <= 0 {
+		return ctx
 	}
+	dctx, cancel := context.WithCancel(context.WithoutCancel(ctx))
+	context.AfterFunc(ctx, func() { time.AfterFunc(drain, cancel) })
+	return dctx
 }
@@ -1,8 +1,10 @@
 package tail //nolint:testpackage // TODO
 
 import (
+	"errors"
 	"io"
 	"os"
+	"os/exec"
 	"runtime"
 	"syscall"
 	"testing"
@@ -142,6 +144,41 @@ func TestNotEmptyGrowBytes(tt *testing.T) {
 	tail.Want(pollDelay*3/2, "ef\ngh", nil)
 }
 
+func TestTruncate(tt *testing.T) {
+	tt.Parallel()
+	t := check.Must(tt)
+	tail := newTestTail(t)
+
+	tail.Write("old1.1\nold1.2\n")
+	tail.Run()
+
+	tail.Write("old2\n")
+	tail.Want(pollDelay*3/2, "old2\n", nil)
+
+	tail.Truncate()
+	tail.Write("new1\n")
+	tail.Want(pollDelay*3/2, "new1\n", nil)
+
+	tail.Truncate()
+	tail.Want(pollDelay*3/2, "", nil)
+	tail.Write("new2\n")
+	tail.Want(pollDelay*3/2, "new2\n", nil)
+}
+
+func TestTruncateError(tt *testing.T) {
+	tt.Parallel()
+	t := check.Must(tt)
+	tail := newTestTail(t)
+
+	tail.Write("old1.1\nold1.2\n")
+	tail.Run(Truncation(TruncateError))
+
+	tail.Truncate()
+	tail.Write("new1\n")
+	tail.Want(pollDelay*3/2, "", ErrTruncated)
+	tail.Want(pollDelay*3/2, "new1\n", nil)
+}
+
 func TestFIFOGrow(tt *testing.T) {
 	tt.Parallel()
 	t := check.Must(tt)
@@ -375,3 +412,80 @@ func TestErrors(tt *testing.T) {
 	tail.Want(pollTimeout-pollDelay*2, "", nil)
 	tail.Want(pollDelay*3, "", syscall.ENOENT)
 }
+
+func TestFollowDescriptor(tt *testing.T) {
+	tt.Parallel()
+	t := check.Must(tt)
+	tail := newTestTail(t)
+
+	tail.Start(FollowDescriptor())
+	tail.Remove()
+	tail.Create()
+	tail.Write("new\n")
+	tail.WriteOld("old1\n")
+	tail.WantChunk("old1\n", nil)
+
+	go func() {
+		time.Sleep(pollDelay * 2)
+		tail.WriteOld("old2\n")
+	}()
+	tail.WantChunk("old2\n", nil)
+}
+
+func TestPID(tt *testing.T) {
+	tt.Parallel()
+	t := check.Must(tt)
+	t.True(processExists(os.Getpid()))
+	cmd := exec.CommandContext(t.Context(), os.Args[0], "-test.run=^$")
+	t.Nil(cmd.Run())
+	pid := cmd.Process.Pid
+	t.False(processExists(pid))
+
+	tail := newTestTail(t)
+	tail.Start(PID(pid))
+	tail.Write("data\n")
+	tail.WantChunk("data\n", nil)
+	tail.WantChunk("", io.EOF)
+	t.NotNil(tail.fctx.Err()) // Files and watcher are released.
+	time.Sleep(pollDelay / 4)
+	_, err := tail.Tail.f.File.Read(make([]byte, 1))
+	t.True(errors.Is(err, os.ErrClosed))
+
+	tail = startTestTail(t, tail.path+".missing", PID(pid))
+	tail.WantChunk("", io.EOF)
+	t.NotNil(tail.fctx.Err())
+}
+
+func TestDrain(tt *testing.T) {
+	tt.Parallel()
+	t := check.Must(tt)
+	tail := newTestTail(t)
+
+	tail.Start(Drain(pollTimeout))
+	tail.Write("a\n")
+	tail.WantChunk("a\n", nil)
+	tail.Rename()
+	tail.Create()
+	tail.Write("c\n")
+	tail.WriteOld("b\n")
+	tail.WantChunk("b\n", nil)
+	tail.WriteOld("x\n")
+
+	tail.Cancel()
+	tail.WantChunk("x\n", nil)
+	tail.WantChunk("c\n", nil)
+	tail.WantChunk("", io.EOF)
+	tail.WantChunk("", io.EOF)
+}
+
+func TestDrainTimeout(tt *testing.T) {
+	tt.Parallel()
+	t := check.Must(tt)
+	tail := newTestTail(t)
+
+	tail.Start(Drain(pollDelay))
+	tail.Write("data\n")
+	tail.Cancel()
+	time.Sleep(pollDelay * 2)
+	tail.WantChunk("", io.EOF)
+}
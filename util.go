@@ -2,9 +2,15 @@ package tail
 
 import (
 	"errors"
+	"log/slog"
 	"os"
+	"time"
 )
 
+// ErrTruncated is returned by Read when file truncation was detected and
+// [TruncateError] mode is used.
+var ErrTruncated = errors.New("file truncated")
+
 // Logger is an interface used to log tail state changes.
 type Logger interface {
 	Printf(format string, v ...any)
@@ -18,6 +24,34 @@ type LoggerFunc func(format string, v ...any)
 // Printf implements Logger interface.
 func (f LoggerFunc) Printf(format string, v ...any) { f(format, v...) }
 
+// logf logs message either using [Slog] logger with given level, msg and
+// attributes for f and err (both are optional, path given to Follow is
+// used without f) or using Logger with given format and v.
+func (t *Tail) logf(level slog.Level, f *trackedFile, err error, msg, format string, v ...any) {
+	if t.slog == nil {
+		t.log.Printf(format, v...)
+		return
+	}
+	attrs := make([]slog.Attr, 0, 4) //nolint:mnd // Max amount of attrs.
+	switch {
+	case f == nil:
+		attrs = append(attrs, slog.String("path", t.path))
+	case f.Opened():
+		attrs = append(attrs, slog.String("path", f.path),
+			slog.Uint64("inode", f.id.Ino), slog.Int64("offset", f.offset))
+	default:
+		attrs = append(attrs, slog.String("path", f.path))
+	}
+	if err != nil {
+		attrs = append(attrs, slog.Any("error", err))
+	}
+	t.slog.LogAttrs(t.ctx, level, msg, attrs...)
+}
+
+// TimestampFunc returns timestamp of the line (without trailing newline)
+// or false if the line has no timestamp.
+type TimestampFunc func(line []byte) (time.Time, bool)
+
 func unwrap(err error) error {
 	var perr *os.PathError
 	switch {
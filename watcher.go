@@ -0,0 +1,114 @@
+package tail
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// Watcher detects changes of followed path.
+type Watcher interface {
+	// Changed returns a channel which becomes ready (receives a value or
+	// is closed) when path or a file opened from path may have changed.
+	//
+	// Tail calls Changed every time it has to wait for changes: either
+	// when path is inaccessible (missing is true) or when all data was
+	// read. Idle is time since Tail has read data, opened a file or
+	// noticed path was renamed or removed, it may be used to adapt
+	// polling delay.
+	//
+	// Changed is never called from simultaneous goroutines.
+	Changed(missing bool, idle time.Duration) <-chan struct{}
+}
+
+// failingWatcher may be implemented by Watcher which may stop working.
+// Once Err returns non-nil error Tail stops using Watcher and falls back
+// to polling every [PollDelay].
+type failingWatcher interface {
+	Err() error
+}
+
+// NewWatcher creates a Watcher for path. Watcher should release its
+// resources after ctx is done.
+type NewWatcher func(ctx context.Context, path string) (Watcher, error)
+
+// PollWatcher returns NewWatcher for Watcher which reports a change after
+// every delay.
+func PollWatcher(delay time.Duration) NewWatcher {
+	return func(context.Context, string) (Watcher, error) {
+		return pollWatcher{delay: delay}, nil
+	}
+}
+
+type pollWatcher struct {
+	delay time.Duration
+}
+
+func (w pollWatcher) Changed(bool, time.Duration) <-chan struct{} {
+	return after(w.delay)
+}
+
+// Backoff defines bounds for adaptive polling delay.
+// Valid bounds must satisfy 0 < Min <= Max.
+type Backoff struct {
+	Min time.Duration
+	Max time.Duration
+}
+
+func (b Backoff) valid() error {
+	if b.Min <= 0 || b.Min > b.Max {
+		return fmt.Errorf("invalid backoff %v..%v: require 0 < Min <= Max", b.Min, b.Max)
+	}
+	return nil
+}
+
+// AdaptivePollWatcher returns NewWatcher for Watcher which reports a
+// change after a delay which grows exponentially while followed file is
+// idle: it's equal to time since Tail has read data, opened a file or
+// noticed path was renamed or removed, but bounded by read (when all data
+// was read) or missing (when path is inaccessible) bounds.
+//
+// Creating this Watcher fails if bounds are invalid.
+func AdaptivePollWatcher(read, missing Backoff) NewWatcher {
+	return func(context.Context, string) (Watcher, error) {
+		if err := errors.Join(read.valid(), missing.valid()); err != nil {
+			return nil, err
+		}
+		return adaptivePollWatcher{read: read, missing: missing}, nil
+	}
+}
+
+type adaptivePollWatcher struct {
+	read    Backoff
+	missing Backoff
+}
+
+func (w adaptivePollWatcher) Changed(missing bool, idle time.Duration) <-chan struct{} {
+	b := w.read
+	if missing {
+		b = w.missing
+	}
+	return after(min(max(idle, b.Min), b.Max))
+}
+
+// InotifyWatcher returns NewWatcher for Watcher which uses inotify on Linux
+// to detect changes. Creating this Watcher fails on other systems.
+//
+// To notice changes not reported by inotify (e.g. on network file system
+// or when following a symlink to another directory) it also reports a
+// change after every rescan delay, which should be much larger than usual
+// [PollDelay]. Inotify queue overflow is reported as a change.
+// If inotify stops working Tail falls back to polling every [PollDelay].
+func InotifyWatcher(rescan time.Duration) NewWatcher {
+	return func(ctx context.Context, path string) (Watcher, error) {
+		return newInotify(ctx, path, rescan)
+	}
+}
+
+// after returns a channel which will be closed after delay.
+func after(delay time.Duration) <-chan struct{} {
+	c := make(chan struct{})
+	time.AfterFunc(delay, func() { close(c) })
+	return c
+}
@@ -0,0 +1,143 @@
+package tail //nolint:testpackage // TODO
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/powerman/check"
+)
+
+type testWatcher struct {
+	c      chan struct{}
+	called chan bool
+}
+
+func (w *testWatcher) Changed(missing bool, _ time.Duration) <-chan struct{} {
+	w.called <- missing
+	return w.c
+}
+
+func TestWatch(tt *testing.T) {
+	tt.Parallel()
+	t := check.Must(tt)
+	tail := newTestTail(t)
+
+	w := &testWatcher{c: make(chan struct{}), called: make(chan bool, 1)}
+	tail.Write("old1\n")
+	tail.Run(Watch(func(context.Context, string) (Watcher, error) { return w, nil }))
+
+	t.False(<-w.called)
+	tail.Write("new1\n")
+	tail.Want(pollTimeout, "", nil)
+	w.c <- struct{}{}
+	tail.Want(pollDelay/2, "new1\n", nil)
+	t.False(<-w.called)
+
+	tail.Remove()
+	tail.Write("new2\n")
+	tail.Want(pollTimeout, "", nil)
+	w.c <- struct{}{}
+	tail.Want(pollDelay/2, "new2\n", nil)
+	t.True(<-w.called)
+	tail.Create()
+	w.c <- struct{}{}
+	t.False(<-w.called)
+	tail.Write("new3\n")
+	w.c <- struct{}{}
+	tail.Want(pollDelay/2, "new3\n", nil)
+}
+
+func TestWatchFailed(tt *testing.T) {
+	tt.Parallel()
+	t := check.Must(tt)
+	tail := newTestTail(t)
+
+	tail.Run(Watch(func(context.Context, string) (Watcher, error) { return nil, errors.ErrUnsupported }))
+
+	tail.Write("new1\n")
+	tail.Want(pollDelay*3/2, "new1\n", nil)
+}
+
+type brokenWatcher struct{}
+
+func (brokenWatcher) Changed(bool, time.Duration) <-chan struct{} { return nil }
+
+func (brokenWatcher) Err() error { return errors.ErrUnsupported }
+
+func TestWatchBroken(tt *testing.T) {
+	tt.Parallel()
+	t := check.Must(tt)
+	tail := newTestTail(t)
+
+	tail.Run(Watch(func(context.Context, string) (Watcher, error) { return brokenWatcher{}, nil }))
+
+	tail.Write("new1\n")
+	tail.Want(pollDelay*3/2, "new1\n", nil)
+}
+
+func TestAdaptivePollWatcher(tt *testing.T) {
+	tt.Parallel()
+	t := check.Must(tt)
+
+	w, err := AdaptivePollWatcher(
+		Backoff{Min: pollDelay / 2, Max: pollDelay * 2},
+		Backoff{Min: pollDelay, Max: pollDelay * 4},
+	)(t.Context(), "")
+	t.Nil(err)
+	tests := []struct {
+		missing bool
+		idle    time.Duration
+		want    time.Duration
+	}{
+		{false, 0, pollDelay / 2},
+		{false, pollDelay, pollDelay},
+		{false, time.Hour, pollDelay * 2},
+		{true, 0, pollDelay},
+		{true, pollDelay * 3, pollDelay * 3},
+		{true, time.Hour, pollDelay * 4},
+	}
+	for _, tc := range tests {
+		start := time.Now()
+		<-w.Changed(tc.missing, tc.idle)
+		t.Between(time.Since(start), tc.want, tc.want+pollDelay/2, "%v %v", tc.missing, tc.idle)
+	}
+}
+
+func TestAdaptivePollWatcherInvalid(tt *testing.T) {
+	tt.Parallel()
+	t := check.Must(tt)
+
+	valid := Backoff{Min: pollDelay, Max: pollDelay}
+	for _, b := range []Backoff{
+		{Min: 0, Max: pollDelay},
+		{Min: pollDelay, Max: 0},
+		{Min: pollDelay * 2, Max: pollDelay},
+		{Min: -pollDelay, Max: pollDelay},
+	} {
+		_, err := AdaptivePollWatcher(b, valid)(t.Context(), "")
+		t.NotNil(err, b)
+		_, err = AdaptivePollWatcher(valid, b)(t.Context(), "")
+		t.NotNil(err, b)
+	}
+}
+
+func TestAdaptivePoll(tt *testing.T) {
+	tt.Parallel()
+	t := check.Must(tt)
+	tail := newTestTail(t)
+
+	tail.Run(Watch(AdaptivePollWatcher(
+		Backoff{Min: pollDelay / 4, Max: pollDelay * 4},
+		Backoff{Min: pollDelay / 4, Max: pollDelay * 4},
+	)))
+
+	tail.Write("new1\n")
+	tail.Want(pollDelay, "new1\n", nil)
+	tail.Write("new2\n")
+	tail.Want(pollDelay, "new2\n", nil)
+	time.Sleep(pollDelay * 6)
+	tail.Write("new3\n")
+	tail.Want(pollDelay*9/2, "new3\n", nil)
+}